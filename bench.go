@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"goburn/burn"
+)
+
+// benchCommand runs the fixed benchmark kernels and prints their scores.
+func benchCommand(args []string) int {
+	fs := newFlagSet("bench", "", `Run fixed, versioned benchmark kernels (integer, floating-point and
+memory latency) for a fixed time, single-threaded and then on every
+thread, and print single-core and multi-core scores, the scaling
+efficiency and the spread between rounds.
+
+A score is the geometric mean of the kernels' rates in million
+iterations per second, summed over threads. Unlike the ops count of
+'goburn run', it only changes when the benchmark version does, so
+scores of the same version compare across machines and releases.`,
+		"  0    benchmark completed\n"+
+			"  1    the benchmark failed or its result could not be written\n"+
+			"  2    invalid flags\n"+
+			"  130  interrupted by SIGINT or SIGTERM\n")
+	duration := fs.Duration("duration", 0, "Time per kernel per round (default 2s)")
+	rounds := fs.Int("rounds", 0, "Rounds per thread configuration (default 3)")
+	threads := fs.Int("threads", 0, "Threads of the multi-core phase (0 = CPUs allowed by GOMAXPROCS and cgroup)")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
+	output := fs.String("o", "", "Also write the JSON result to this file")
+	if ok, code := parseFlags(fs, args); !ok {
+		return code
+	}
+	if *duration < 0 || *rounds < 0 || *threads < 0 {
+		fmt.Fprintln(os.Stderr, "goburn bench: -duration, -rounds and -threads must not be negative")
+		return exitUsage
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if !*asJSON {
+		fmt.Printf("goburn bench v%d\n", burn.BenchVersion)
+	}
+	result, err := burn.RunBench(ctx, burn.BenchConfig{Duration: *duration, Rounds: *rounds, Threads: *threads})
+	if errors.Is(err, context.Canceled) {
+		return exitInterrupted
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn bench: %v\n", err)
+		return exitFailure
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn bench: %v\n", err)
+		return exitFailure
+	}
+	if *output != "" {
+		if err := os.WriteFile(*output, append(data, '\n'), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "goburn bench: %v\n", err)
+			return exitFailure
+		}
+	}
+	if *asJSON {
+		fmt.Println(string(data))
+		return exitOK
+	}
+
+	if machine := result.Inventory.Short(); machine != "" {
+		fmt.Printf("machine: %s\n", machine)
+	}
+	fmt.Printf("%d rounds of %s per kernel\n\n", len(result.Single.Rounds), result.Duration)
+
+	kernels := make([]string, 0, len(result.Single.Kernels))
+	for name := range result.Single.Kernels {
+		kernels = append(kernels, name)
+	}
+	sort.Strings(kernels)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	header := []string{"", "THREADS", "SCORE", "STDDEV", "CV"}
+	for _, k := range kernels {
+		header = append(header, k+" M/s")
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range []struct {
+		name    string
+		threads int
+		score   burn.BenchScore
+	}{
+		{"single-core", 1, result.Single},
+		{"multi-core", result.Threads, result.Multi},
+	} {
+		cells := []string{row.name, fmt.Sprint(row.threads), fmt.Sprintf("%.1f", row.score.Score),
+			fmt.Sprintf("%.2f", row.score.StdDev), fmt.Sprintf("%.1f%%", row.score.CV())}
+		for _, k := range kernels {
+			cells = append(cells, fmt.Sprintf("%.1f", row.score.Kernels[k]))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+
+	fmt.Printf("\nscaling efficiency: %.1f%% of %d × single-core\n", result.Scaling, result.Threads)
+	return exitOK
+}
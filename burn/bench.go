@@ -0,0 +1,225 @@
+package burn
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"goburn/hardware"
+)
+
+// BenchVersion identifies the benchmark kernels and scoring. Scores are
+// only comparable between runs of the same version; any change to a
+// kernel, its chunk size or the score formula must increment it.
+const BenchVersion = 1
+
+// BenchConfig describes a benchmark. The zero value runs every kernel for
+// two seconds per round, three rounds, on every available CPU.
+type BenchConfig struct {
+	Duration time.Duration // Time per kernel per round, 0 for two seconds
+	Rounds   int           // Rounds per thread configuration, 0 for three
+	Threads  int           // Threads of the multi-core phase, 0 for DefaultWorkers
+}
+
+// BenchResult is the outcome of a benchmark.
+type BenchResult struct {
+	Version   int                `json:"version"` // BenchVersion the scores belong to
+	Inventory hardware.Inventory `json:"inventory"`
+	Duration  time.Duration      `json:"duration_ns"` // Time per kernel per round
+	Threads   int                `json:"threads"`     // Threads of the multi-core phase
+	Single    BenchScore         `json:"single"`
+	Multi     BenchScore         `json:"multi"`
+	Scaling   float64            `json:"scaling_pct"` // Multi score over Threads times the single score
+}
+
+// BenchScore is the score of one thread configuration. A round's score is
+// the geometric mean of the kernels' rates in million iterations per
+// second, summed over threads.
+type BenchScore struct {
+	Score   float64            `json:"score"`   // Mean of the round scores
+	StdDev  float64            `json:"stddev"`  // Sample standard deviation of the round scores
+	Rounds  []float64          `json:"rounds"`  // Score of every round
+	Kernels map[string]float64 `json:"kernels"` // Mean rate of every kernel, in M iterations/s
+}
+
+// CV returns the coefficient of variation of the rounds, in percent.
+func (s BenchScore) CV() float64 {
+	if s.Score == 0 {
+		return 0
+	}
+	return s.StdDev / s.Score * 100
+}
+
+// benchKernel is a fixed CPU kernel of the benchmark. New returns a
+// function running n iterations from state x, with its own scratch memory.
+type benchKernel struct {
+	name string
+	new  func() func(n int, x uint64) uint64
+}
+
+// benchChunk is the number of iterations between deadline checks.
+const benchChunk = 4096
+
+// benchKernels are the kernels of BenchVersion 1, in scoring order. They
+// avoid library calls so that their cost does not change with Go releases.
+var benchKernels = []benchKernel{
+	{"int", func() func(int, uint64) uint64 {
+		// xorshift64* chain: integer ALU and multiplier
+		return func(n int, x uint64) uint64 {
+			x |= 1
+			for i := 0; i < n; i++ {
+				x ^= x << 13
+				x ^= x >> 7
+				x ^= x << 17
+				x *= 0x2545F4914F6CDD1D
+			}
+			return x
+		}
+	}},
+	{"float", func() func(int, uint64) uint64 {
+		// Dependent multiply, add and divide chain: FPU latency
+		return func(n int, x uint64) uint64 {
+			v := float64(x%1000) + 1
+			for i := 0; i < n; i++ {
+				v = (v*v + 1.5) / (v + 2.5)
+			}
+			return x + uint64(v)
+		}
+	}},
+	{"mem", func() func(int, uint64) uint64 {
+		// Dependent loads over 128 KiB (32768 uint32s): cache latency
+		const size = 1 << 15
+		next := make([]uint32, size)
+		for i := range next {
+			next[i] = uint32((i*31677 + 1) % size) // Full-period LCG step
+		}
+		return func(n int, x uint64) uint64 {
+			j := uint32(x % size)
+			for i := 0; i < n; i++ {
+				j = next[j]
+			}
+			return x + uint64(j)
+		}
+	}},
+}
+
+// benchSink keeps kernel results alive so the compiler cannot drop them.
+var benchSink atomic.Uint64
+
+// RunBench runs the benchmark kernels single-threaded, then on cfg.Threads
+// threads, and scores both. It returns ctx.Err() if ctx is cancelled.
+func RunBench(ctx context.Context, cfg BenchConfig) (BenchResult, error) {
+	if cfg.Duration < 0 || cfg.Rounds < 0 || cfg.Threads < 0 {
+		return BenchResult{}, errors.New("burn: negative benchmark setting")
+	}
+	if cfg.Duration == 0 {
+		cfg.Duration = 2 * time.Second
+	}
+	if cfg.Rounds == 0 {
+		cfg.Rounds = 3
+	}
+	if cfg.Threads == 0 {
+		cfg.Threads = DefaultWorkers(hardware.GetCgroupLimits())
+	}
+
+	// Every thread needs a P to run at the same time as the others
+	if procs := runtime.GOMAXPROCS(0); cfg.Threads > procs {
+		runtime.GOMAXPROCS(cfg.Threads)
+		defer runtime.GOMAXPROCS(procs)
+	}
+
+	result := BenchResult{
+		Version:   BenchVersion,
+		Inventory: hardware.GetInventory(),
+		Duration:  cfg.Duration,
+		Threads:   cfg.Threads,
+	}
+	var err error
+	if result.Single, err = benchScore(ctx, 1, cfg); err != nil {
+		return result, err
+	}
+	if result.Multi, err = benchScore(ctx, cfg.Threads, cfg); err != nil {
+		return result, err
+	}
+	if result.Single.Score > 0 {
+		result.Scaling = result.Multi.Score / (result.Single.Score * float64(cfg.Threads)) * 100
+	}
+	return result, nil
+}
+
+// benchScore runs every round of one thread configuration.
+func benchScore(ctx context.Context, threads int, cfg BenchConfig) (BenchScore, error) {
+	score := BenchScore{Kernels: make(map[string]float64)}
+	for round := 0; round < cfg.Rounds; round++ {
+		logSum := 0.0
+		for _, k := range benchKernels {
+			rate, err := benchKernelRate(ctx, k, threads, cfg.Duration)
+			if err != nil {
+				return score, err
+			}
+			score.Kernels[k.name] += rate / float64(cfg.Rounds)
+			logSum += math.Log(rate)
+		}
+		score.Rounds = append(score.Rounds, math.Exp(logSum/float64(len(benchKernels))))
+	}
+
+	for _, r := range score.Rounds {
+		score.Score += r / float64(len(score.Rounds))
+	}
+	if n := len(score.Rounds); n > 1 {
+		for _, r := range score.Rounds {
+			score.StdDev += (r - score.Score) * (r - score.Score)
+		}
+		score.StdDev = math.Sqrt(score.StdDev / float64(n-1))
+	}
+	return score, nil
+}
+
+// benchKernelRate runs kernel k on threads locked OS threads for d and
+// returns the total rate in million iterations per second. Every thread
+// counts exact iterations over its own measured time.
+func benchKernelRate(ctx context.Context, k benchKernel, threads int, d time.Duration) (float64, error) {
+	rates := make([]float64, threads)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for t := 0; t < threads; t++ {
+		wg.Add(1)
+		go func(t int) {
+			defer wg.Done()
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+
+			run := k.new()
+			x := uint64(t + 1)
+			<-start
+
+			var iters int
+			begin := time.Now()
+			deadline := begin.Add(d)
+			for now := begin; now.Before(deadline); now = time.Now() {
+				if ctx.Err() != nil {
+					return
+				}
+				x = run(benchChunk, x)
+				iters += benchChunk
+			}
+			rates[t] = float64(iters) / time.Since(begin).Seconds() / 1_000_000
+			benchSink.Add(x)
+		}(t)
+	}
+	close(start)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, r := range rates {
+		total += r
+	}
+	return total, nil
+}
@@ -0,0 +1,135 @@
+package burn
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"goburn/hardware"
+	"goburn/worker"
+)
+
+// Binding pins workers and their memory to logical CPUs and NUMA nodes.
+// The zero value leaves both to the scheduler and the kernel.
+type Binding struct {
+	// Placement orders the CPUs workers are pinned to. Empty pins nothing,
+	// unless one of the other fields is set; then it defaults to "node".
+	Placement hardware.Placement `json:"placement,omitempty"`
+	Nodes     []int              `json:"nodes,omitempty"`      // NUMA nodes whose CPUs run workers, nil for all
+	MemNodes  []int              `json:"mem_nodes,omitempty"`  // NUMA nodes holding worker memory, round-robin; nil for each worker's own
+	CrossNode bool               `json:"cross_node,omitempty"` // Hold each worker's memory on the node after its own
+}
+
+// Pinned reports whether the binding pins workers.
+func (b Binding) Pinned() bool {
+	return b.Placement != "" || len(b.Nodes) > 0 || len(b.MemNodes) > 0 || b.CrossNode
+}
+
+// String describes the binding, such as "spread placement on node 0,
+// memory on node 1". Returns an empty string if nothing is pinned.
+func (b Binding) String() string {
+	if !b.Pinned() {
+		return ""
+	}
+	parts := []string{string(b.placement()) + " placement"}
+	if len(b.Nodes) > 0 {
+		parts[0] += " on node " + hardware.FormatCPUList(b.Nodes)
+	}
+	switch {
+	case len(b.MemNodes) > 0:
+		parts = append(parts, "memory on node "+hardware.FormatCPUList(b.MemNodes))
+	case b.CrossNode:
+		parts = append(parts, "memory on the next node")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// placement returns the placement strategy in effect.
+func (b Binding) placement() hardware.Placement {
+	if b.Placement == "" {
+		return hardware.PlaceNode
+	}
+	return b.Placement
+}
+
+// Options returns opts with the worker options that apply the binding on
+// this machine. Without sysfs topology, workers are left unpinned.
+func (b Binding) Options(opts []worker.Option) ([]worker.Option, error) {
+	if !b.Pinned() {
+		return opts, nil
+	}
+	if _, ok := hardware.ParsePlacement(string(b.placement())); !ok {
+		return nil, fmt.Errorf("burn: unknown placement %q", b.Placement)
+	}
+	topo := hardware.GetTopology()
+	if len(topo.CPUs) == 0 {
+		return opts, nil
+	}
+
+	order := b.Order(topo)
+	if len(order) == 0 {
+		return nil, fmt.Errorf("burn: no CPUs on NUMA node %s", hardware.FormatCPUList(b.Nodes))
+	}
+	opts = append(slices.Clone(opts), worker.WithCPUs(order))
+
+	memCPU, err := b.memoryCPUs(topo)
+	if err != nil {
+		return nil, err
+	}
+	if memCPU != nil {
+		opts = append(opts, worker.WithMemoryCPUs(memCPU))
+	}
+	return opts, nil
+}
+
+// Order returns the logical CPUs of topo that workers are pinned to, in
+// the order of the placement, or nil if the binding pins nothing.
+func (b Binding) Order(topo hardware.Topology) []int {
+	if !b.Pinned() {
+		return nil
+	}
+	if len(b.Nodes) > 0 {
+		topo = topo.OnNodes(b.Nodes)
+	}
+	return topo.Place(b.placement())
+}
+
+// memoryCPUs returns where workers touch their memory first, or nil to
+// keep it on their own node. Memory of a node is touched from its first
+// CPU.
+func (b Binding) memoryCPUs(topo hardware.Topology) (func(worker, cpu int) int, error) {
+	firstCPU := make(map[int]int)
+	for _, cpu := range topo.CPUs {
+		if _, ok := firstCPU[cpu.Node]; !ok {
+			firstCPU[cpu.Node] = cpu.ID
+		}
+	}
+
+	switch {
+	case len(b.MemNodes) > 0:
+		cpus := make([]int, len(b.MemNodes))
+		for i, node := range b.MemNodes {
+			cpu, ok := firstCPU[node]
+			if !ok {
+				return nil, fmt.Errorf("burn: NUMA node %d has no CPUs to place memory from", node)
+			}
+			cpus[i] = cpu
+		}
+		return func(worker, _ int) int { return cpus[worker%len(cpus)] }, nil
+
+	case b.CrossNode:
+		nodes := topo.NodeIDs()
+		if len(nodes) < 2 {
+			return nil, nil
+		}
+		nodeOf := make(map[int]int, len(topo.CPUs))
+		for _, cpu := range topo.CPUs {
+			nodeOf[cpu.ID] = cpu.Node
+		}
+		return func(_, cpu int) int {
+			next := nodes[(slices.Index(nodes, nodeOf[cpu])+1)%len(nodes)]
+			return firstCPU[next]
+		}, nil
+	}
+	return nil, nil
+}
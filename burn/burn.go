@@ -0,0 +1,158 @@
+// Package burn runs CPU burn tests from Go code, for example to check
+// that a service behaves under CPU pressure in an integration suite.
+//
+// It drives a worker.Pool and samples hardware.Get, reporting through
+// callbacks and a returned Report. It never writes to the terminal and
+// never exits the process.
+package burn
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"time"
+
+	"goburn/hardware"
+	"goburn/worker"
+)
+
+// Config describes a burn run. The zero value burns every available CPU,
+// sampling once per second, until the context is cancelled.
+type Config struct {
+	Duration time.Duration // Run length, 0 to run until ctx is cancelled
+	Warmup   time.Duration // Initial part of the run left out of the summary
+
+	// UntilSteady ends the run this long after steady state is detected,
+	// and extends the warmup to the detection. Duration still caps the run.
+	// 0 runs for Duration whether or not steady state is reached.
+	UntilSteady time.Duration
+	Workers     int           // Worker count, 0 for GOMAXPROCS capped by cgroup limits
+	Interval    time.Duration // Sample interval, 0 for one second
+
+	// Binding pins workers and their memory to CPUs and NUMA nodes.
+	// The zero value leaves placement to the scheduler.
+	Binding Binding
+
+	// PoolOptions are passed to worker.New.
+	PoolOptions []worker.Option
+
+	// OnStart is called with the report header (machine inventory, power
+	// settings and cgroup limits) before the workers start.
+	OnStart func(Report)
+
+	// OnSample is called from the Run goroutine after every sample.
+	OnSample func(Sample)
+
+	// Markers receives labels to mark on the run's timeline, such as
+	// "fan curve changed". Nil for none.
+	Markers <-chan string
+
+	// OnMarker is called from the Run goroutine after every marker.
+	OnMarker func(Marker)
+}
+
+// DefaultWorkers returns the worker count used when Config.Workers is 0:
+// runtime.GOMAXPROCS, capped by the cgroup v2 CPU quota and cpuset.
+func DefaultWorkers(limits hardware.CgroupLimits) int {
+	n := runtime.GOMAXPROCS(-1)
+	if limit := limits.CPULimit(); limit > 0 && limit < n {
+		n = limit
+	}
+	return n
+}
+
+// Run burns CPU as described by cfg and returns the recorded report.
+// If ctx is cancelled before Duration elapses, Run stops the workers and
+// returns the partial report together with ctx.Err(). With a zero
+// Duration, cancellation is the normal way to end the run, and callers
+// decide whether that error means failure.
+func Run(ctx context.Context, cfg Config) (Report, error) {
+	if cfg.Duration < 0 {
+		return Report{}, errors.New("burn: negative duration")
+	}
+	if cfg.Workers < 0 {
+		return Report{}, errors.New("burn: negative worker count")
+	}
+	if cfg.Interval < 0 {
+		return Report{}, errors.New("burn: negative sample interval")
+	}
+	if cfg.Warmup < 0 {
+		return Report{}, errors.New("burn: negative warmup")
+	}
+	if cfg.UntilSteady < 0 {
+		return Report{}, errors.New("burn: negative until-steady time")
+	}
+	poolOptions, err := cfg.Binding.Options(cfg.PoolOptions)
+	if err != nil {
+		return Report{}, err
+	}
+
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = time.Second
+	}
+	limits := hardware.GetCgroupLimits()
+	workers := cfg.Workers
+	if workers == 0 {
+		workers = DefaultWorkers(limits)
+	}
+
+	header := Report{
+		Duration:  cfg.Duration,
+		Warmup:    cfg.Warmup,
+		Workers:   workers,
+		Binding:   cfg.Binding,
+		Inventory: hardware.GetInventory(),
+		Power:     hardware.GetPowerSettings(),
+		Cgroup:    limits,
+	}
+	if cfg.OnStart != nil {
+		cfg.OnStart(header)
+	}
+
+	var counter uint64
+	header.Start = time.Now()
+	pool := worker.New(ctx, &counter, workers, poolOptions...)
+	defer pool.Stop()
+
+	sampler := NewSampler(pool, header.Start)
+	sampler.SetWarmup(cfg.Warmup)
+	recorder := NewRecorder(header)
+	steady := NewSteadyTracker(sampler, recorder, cfg.Warmup, cfg.UntilSteady)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	markers := cfg.Markers
+	for {
+		select {
+		case <-ctx.Done():
+			return recorder.Report(time.Now()), ctx.Err()
+		case label, ok := <-markers:
+			if !ok {
+				markers = nil
+				continue
+			}
+			marker := recorder.Mark(time.Since(header.Start), label)
+			if cfg.OnMarker != nil {
+				cfg.OnMarker(marker)
+			}
+			continue
+		case <-ticker.C:
+		}
+
+		sample := sampler.Sample()
+		recorder.Add(sample)
+		steady.Add(sample)
+		if cfg.OnSample != nil {
+			cfg.OnSample(sample)
+		}
+
+		if cfg.Duration > 0 && sample.Elapsed >= cfg.Duration {
+			return recorder.Report(time.Now()), nil
+		}
+		if steady.Done(sample.Elapsed) {
+			return recorder.Report(time.Now()), nil
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package burn
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"goburn/hardware"
+)
+
+func TestRunDuration(t *testing.T) {
+	var seen int
+	report, err := Run(context.Background(), Config{
+		Duration: 100 * time.Millisecond,
+		Workers:  2,
+		Interval: 20 * time.Millisecond,
+		OnSample: func(s Sample) {
+			seen++
+			if s.Workers != 2 {
+				t.Errorf("sample workers = %d, want 2", s.Workers)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if seen == 0 || seen != len(report.Samples) {
+		t.Fatalf("OnSample called %d times for %d samples", seen, len(report.Samples))
+	}
+	if last := report.Samples[len(report.Samples)-1]; last.Elapsed < 100*time.Millisecond {
+		t.Fatalf("run ended early at %s", last.Elapsed)
+	}
+	if report.Workers != 2 || !report.End.After(report.Start) {
+		t.Fatalf("bad report header: %+v", report)
+	}
+	if report.Summary.OpsPerSec.Count != len(report.Samples) {
+		t.Fatalf("summary covers %d samples, want %d", report.Summary.OpsPerSec.Count, len(report.Samples))
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Run(ctx, Config{Duration: time.Hour, Workers: 1, Interval: 10 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run error = %v, want deadline exceeded", err)
+	}
+
+	// Without a duration, cancellation still reports ctx.Err() with the report
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	report, err := Run(ctx, Config{Workers: 1, Interval: 10 * time.Millisecond})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run without duration error = %v, want canceled", err)
+	}
+	if len(report.Samples) == 0 {
+		t.Fatal("Run without duration returned no samples")
+	}
+}
+
+func TestRunWarmup(t *testing.T) {
+	report, err := Run(context.Background(), Config{
+		Duration: 100 * time.Millisecond,
+		Warmup:   50 * time.Millisecond,
+		Workers:  1,
+		Interval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	warmup := 0
+	for _, s := range report.Samples {
+		if s.Warmup != (s.Elapsed-s.Interval < 50*time.Millisecond) {
+			t.Errorf("sample at %s: warmup = %v", s.Elapsed, s.Warmup)
+		}
+		if s.Warmup {
+			warmup++
+		}
+	}
+	if warmup == 0 || report.Summary.OpsPerSec.Count != len(report.Samples)-warmup {
+		t.Fatalf("%d warmup samples, summary covers %d of %d", warmup, report.Summary.OpsPerSec.Count, len(report.Samples))
+	}
+}
+
+func TestRunMarkers(t *testing.T) {
+	markers := make(chan string)
+	var marked []Marker
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(50 * time.Millisecond)
+		markers <- "fan curve changed"
+		close(markers)
+	}()
+
+	report, err := Run(context.Background(), Config{
+		Duration: 100 * time.Millisecond,
+		Workers:  1,
+		Interval: 10 * time.Millisecond,
+		Markers:  markers,
+		OnMarker: func(m Marker) { marked = append(marked, m) },
+	})
+	<-done
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Markers) != 1 || len(marked) != 1 {
+		t.Fatalf("got %d markers, %d callbacks, want 1", len(report.Markers), len(marked))
+	}
+	m := report.Markers[0]
+	if m.Label != "fan curve changed" || m.Elapsed < 50*time.Millisecond {
+		t.Errorf("marker = %q at %s", m.Label, m.Elapsed)
+	}
+	if m.Sample.Elapsed == 0 || m.Sample.Elapsed > m.Elapsed {
+		t.Errorf("marker sample at %s, want before %s", m.Sample.Elapsed, m.Elapsed)
+	}
+}
+
+func TestRunSweep(t *testing.T) {
+	var steps []SweepStep
+	result, err := RunSweep(context.Background(), SweepConfig{
+		Steps:    []int{1, 2},
+		Warmup:   30 * time.Millisecond,
+		Hold:     60 * time.Millisecond,
+		Interval: 10 * time.Millisecond,
+		OnStep:   func(s SweepStep) { steps = append(steps, s) },
+	})
+	if err != nil {
+		t.Fatalf("RunSweep: %v", err)
+	}
+	if len(result.Steps) != 2 || len(steps) != 2 {
+		t.Fatalf("got %d steps, %d callbacks, want 2", len(result.Steps), len(steps))
+	}
+	for i, s := range result.Steps {
+		if s.Workers != i+1 || s.Summary.OpsPerSec.Count == 0 || s.OpsPerWorker <= 0 {
+			t.Errorf("step %d = %+v", i, s)
+		}
+	}
+	if first := result.Steps[0]; first.Speedup != 1 || first.Efficiency != 100 {
+		t.Errorf("first step speedup %.2f, efficiency %.1f%%, want 1 and 100%%", first.Speedup, first.Efficiency)
+	}
+
+	// Every step starts with a marker and its warmup samples
+	report := result.Report
+	if len(report.Markers) != 2 || report.Markers[1].Label != "2 workers" {
+		t.Fatalf("markers = %+v", report.Markers)
+	}
+	for _, s := range report.Samples {
+		if s.Elapsed-s.Interval < report.Markers[1].Elapsed+30*time.Millisecond && s.Elapsed > report.Markers[1].Elapsed && !s.Warmup {
+			t.Errorf("sample at %s after the step change is not warmup", s.Elapsed)
+		}
+	}
+
+	var csv strings.Builder
+	if err := result.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[2], "2,") {
+		t.Errorf("CSV = %q", csv.String())
+	}
+
+	if _, err := RunSweep(context.Background(), SweepConfig{Steps: []int{1, 0}}); err == nil {
+		t.Error("RunSweep with a zero step succeeded, want error")
+	}
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	for _, cfg := range []Config{
+		{Duration: -time.Second},
+		{Workers: -1},
+		{Interval: -time.Second},
+		{Warmup: -time.Second},
+	} {
+		if _, err := Run(context.Background(), cfg); err == nil {
+			t.Errorf("Run(%+v) succeeded, want error", cfg)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	samples := []Sample{
+		{OpsPerSec: 100, Stats: hardware.Stats{CPUFreqCur: 2000, CPUFreqMax: 4000, Temperature: 50, FanRPMs: []int{1000, 2000}}},
+		{OpsPerSec: 300, Stats: hardware.Stats{CPUFreqCur: 4000, CPUFreqMax: 4000}, Throttled: time.Millisecond},
+	}
+	sum := Summarize(samples)
+
+	if sum.OpsPerSec != (Stat{Min: 100, Avg: 200, Max: 300, Count: 2}) {
+		t.Errorf("ops = %+v", sum.OpsPerSec)
+	}
+	if sum.CPUFreqMHz != (Stat{Min: 2000, Avg: 3000, Max: 4000, Count: 2}) {
+		t.Errorf("freq = %+v", sum.CPUFreqMHz)
+	}
+	if sum.Temperature != (Stat{Min: 50, Avg: 50, Max: 50, Count: 1}) {
+		t.Errorf("temp = %+v", sum.Temperature)
+	}
+	if sum.FanRPM != (Stat{Min: 1500, Avg: 1500, Max: 1500, Count: 1}) {
+		t.Errorf("fans = %+v", sum.FanRPM)
+	}
+	if sum.Throttled != time.Millisecond {
+		t.Errorf("throttled = %s", sum.Throttled)
+	}
+
+	// Warmup samples count towards nothing
+	sum = Summarize(append([]Sample{{OpsPerSec: 900, Warmup: true, Stats: hardware.Stats{Temperature: 90}}}, samples...))
+	if sum.OpsPerSec.Max != 300 || sum.Temperature.Max != 50 {
+		t.Errorf("summary with a warmup sample = %+v", sum)
+	}
+
+	// Paused samples count towards everything but throughput
+	samples = append(samples, Sample{OpsPerSec: 1, Paused: time.Second, Stats: hardware.Stats{Temperature: 40}})
+	sum = Summarize(samples)
+	if sum.OpsPerSec.Count != 2 || sum.OpsPerSec.Min != 100 {
+		t.Errorf("ops with a paused sample = %+v", sum.OpsPerSec)
+	}
+	if sum.Temperature.Count != 2 || sum.Paused != time.Second {
+		t.Errorf("paused sample summary = %+v", sum)
+	}
+
+	// Sockets are summarized apart, by package ID
+	sum = Summarize([]Sample{
+		{Sockets: []SocketSample{{Package: 0, OpsPerSec: 10, Temperature: 60, PackageWatts: 80}, {Package: 1, CPUFreqMHz: 3000, Temperature: 70}}},
+		{Sockets: []SocketSample{{Package: 0, OpsPerSec: 30, Temperature: 62}, {Package: 1, OpsPerSec: 5}}},
+	})
+	want := []SocketSummary{
+		{Package: 0, OpsPerSec: Stat{Min: 10, Avg: 20, Max: 30, Count: 2}, Temperature: Stat{Min: 60, Avg: 61, Max: 62, Count: 2},
+			Power: Stat{Min: 80, Avg: 80, Max: 80, Count: 1}},
+		{Package: 1, OpsPerSec: Stat{Min: 5, Avg: 5, Max: 5, Count: 1}, CPUFreqMHz: Stat{Min: 3000, Avg: 3000, Max: 3000, Count: 1},
+			Temperature: Stat{Min: 70, Avg: 70, Max: 70, Count: 1}},
+	}
+	if !reflect.DeepEqual(sum.Sockets, want) {
+		t.Errorf("sockets = %+v, want %+v", sum.Sockets, want)
+	}
+}
+
+func TestBindingString(t *testing.T) {
+	for _, tc := range []struct {
+		b    Binding
+		want string
+	}{
+		{Binding{}, ""},
+		{Binding{Placement: hardware.PlaceSpread}, "spread placement"},
+		{Binding{Nodes: []int{0, 1}}, "node placement on node 0-1"},
+		{Binding{MemNodes: []int{1}}, "node placement, memory on node 1"},
+		{Binding{Nodes: []int{0}, CrossNode: true}, "node placement on node 0, memory on the next node"},
+	} {
+		if got := tc.b.String(); got != tc.want {
+			t.Errorf("%+v.String() = %q, want %q", tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestReadReport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.json")
+	want := Report{Workers: 4, Binding: Binding{Placement: hardware.PlaceNode, MemNodes: []int{1}}}
+	if err := want.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadReport(path); err != nil || got.Workers != 4 || got.Binding.String() != want.Binding.String() {
+		t.Errorf("ReadReport = %+v, %v; want binding %q", got, err, want.Binding)
+	}
+
+	// Older reports have the placement as a top-level field
+	legacy := filepath.Join(dir, "legacy.json")
+	if err := os.WriteFile(legacy, []byte(`{"workers":2,"placement":"spread"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadReport(legacy); err != nil || got.Binding.Placement != hardware.PlaceSpread {
+		t.Errorf("ReadReport of a placement-only report = %+v, %v; want spread placement", got.Binding, err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	run := func(ops ...float64) Report {
+		r := Report{Samples: []Sample{{OpsPerSec: 1e9, Warmup: true}}}
+		for i, v := range ops {
+			r.Samples = append(r.Samples, Sample{Elapsed: time.Duration(i+1) * time.Second, OpsPerSec: v,
+				Stats: hardware.Stats{Temperature: 60}})
+		}
+		return r
+	}
+	base := run(100, 102, 98, 101, 99)
+
+	deltas := Compare(base, run(101, 99, 100, 98, 102))
+	if len(deltas) != 2 || deltas[0].Metric.Name != "ops" || deltas[1].Metric.Name != "temp" {
+		t.Fatalf("deltas = %+v", deltas)
+	}
+	if deltas[0].Hint != Noise || deltas[1].Hint != Noise {
+		t.Errorf("hints of equal runs = %q, %q", deltas[0].Hint, deltas[1].Hint)
+	}
+
+	// The warmup sample must not mask a 10% drop
+	d := Compare(base, run(90, 92, 88, 91, 89))[0]
+	if d.Hint != Significant || d.Change != -10 || d.ChangePct != -10 || d.T >= 0 {
+		t.Errorf("delta of a slower run = %+v", d)
+	}
+
+	if d := Compare(base, run(90))[0]; d.Hint != TooFew {
+		t.Errorf("hint with one sample = %q", d.Hint)
+	}
+	if deltas := Compare(base, Report{}); len(deltas) != 0 {
+		t.Errorf("deltas against an empty run = %+v", deltas)
+	}
+}
+
+func TestSteadyDetector(t *testing.T) {
+	// Temperature climbs for two minutes, then plateaus with some noise
+	d := NewSteadyDetector()
+	var reached time.Duration
+	for i := 1; i <= 300; i++ {
+		at := time.Duration(i) * time.Second
+		temp := 50 + min(float64(i), 120)/4 + float64(i%2)*0.5
+		ops := 1e9 + float64(i%3)*1e7
+		s := Sample{Elapsed: at, Interval: time.Second, OpsPerSec: ops, Stats: hardware.Stats{Temperature: temp}}
+		if d.Add(s) {
+			reached = at
+		}
+	}
+	at, ok := d.Steady()
+	if !ok || reached < 150*time.Second || reached > 4*time.Minute || at != reached-time.Minute {
+		t.Fatalf("steady at %s (detected at %s, %v), want about 2m", at, reached, ok)
+	}
+
+	// Without a sensor, flat ops are steady after one window.
+	// A pause restarts the window.
+	d = NewSteadyDetector()
+	for i := 1; i <= 90; i++ {
+		s := Sample{Elapsed: time.Duration(i) * time.Second, Interval: time.Second, OpsPerSec: 1e9}
+		if i == 30 {
+			s.Paused = time.Second
+		}
+		d.Add(s)
+	}
+	if at, ok := d.Steady(); !ok || at != 30*time.Second {
+		t.Fatalf("steady at %s, %v, want 30s", at, ok)
+	}
+}
+
+func TestSteadyTracker(t *testing.T) {
+	// Flat ops are steady after one window; with -until-steady shorter than
+	// the window, the run must still measure that long after detection
+	recorder := NewRecorder(Report{})
+	tracker := NewSteadyTracker(&Sampler{}, recorder, 0, 30*time.Second)
+	var last time.Duration
+	for i := 1; i <= 300 && !tracker.Done(last); i++ {
+		last = time.Duration(i) * time.Second
+		s := Sample{Elapsed: last, Interval: time.Second, OpsPerSec: 1e9, Warmup: last-time.Second < tracker.Warmup()}
+		recorder.Add(s)
+		tracker.Add(s)
+	}
+
+	report := recorder.Report(time.Now())
+	if !report.Steady || report.SteadyAt != 0 || report.Warmup != time.Minute {
+		t.Errorf("steady %v at %s, warmup %s; want steady at 0s, warmup 1m", report.Steady, report.SteadyAt, report.Warmup)
+	}
+	if end, ok := tracker.End(); !ok || end != 90*time.Second || last != end {
+		t.Errorf("run ended at %s, end %s %v; want 1m30s", last, end, ok)
+	}
+	measured := 0
+	for _, s := range report.Samples {
+		if !s.Warmup {
+			measured++
+		}
+	}
+	if measured != 30 {
+		t.Errorf("%d samples measured after steady state, want 30", measured)
+	}
+
+	// Without UntilSteady, steady state is only recorded
+	recorder = NewRecorder(Report{Warmup: 5 * time.Second})
+	tracker = NewSteadyTracker(&Sampler{}, recorder, 5*time.Second, 0)
+	for i := 1; i <= 90; i++ {
+		s := Sample{Elapsed: time.Duration(i) * time.Second, Interval: time.Second, OpsPerSec: 1e9}
+		recorder.Add(s)
+		tracker.Add(s)
+	}
+	if _, ok := tracker.End(); ok || tracker.Done(time.Hour) || tracker.Warmup() != 5*time.Second {
+		t.Errorf("tracker without UntilSteady ends the run or moves the warmup to %s", tracker.Warmup())
+	}
+	if report := recorder.Report(time.Now()); !report.Steady || report.Warmup != 5*time.Second {
+		t.Errorf("steady %v, warmup %s; want steady, warmup 5s", report.Steady, report.Warmup)
+	}
+}
+
+func TestRunBench(t *testing.T) {
+	result, err := RunBench(context.Background(), BenchConfig{Duration: 20 * time.Millisecond, Rounds: 2, Threads: 2})
+	if err != nil {
+		t.Fatalf("RunBench: %v", err)
+	}
+	if result.Version != BenchVersion || result.Threads != 2 {
+		t.Fatalf("result header = %+v", result)
+	}
+	for name, s := range map[string]BenchScore{"single": result.Single, "multi": result.Multi} {
+		if len(s.Rounds) != 2 || s.Score <= 0 || len(s.Kernels) != len(benchKernels) {
+			t.Errorf("%s score = %+v", name, s)
+		}
+	}
+	if result.Scaling <= 0 {
+		t.Errorf("scaling = %.1f%%", result.Scaling)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := RunBench(ctx, BenchConfig{Duration: time.Second}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled RunBench error = %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package burn
+
+import (
+	"math"
+)
+
+// Metric is a per-sample measurement that runs can be compared on.
+type Metric struct {
+	Name  string  // Short name, such as "ops"
+	Label string  // Display name with unit, such as "ops (M/s)"
+	Scale float64 // Divides values for display
+	// HigherIsBetter tells whether an increase is an improvement.
+	HigherIsBetter bool
+
+	value func(Sample) (float64, bool)
+}
+
+// Value returns the metric of a sample, or false if the sample does not
+// have it. Warmup samples never do, nor do paused samples for ops.
+func (m Metric) Value(s Sample) (float64, bool) {
+	if s.Warmup {
+		return 0, false
+	}
+	return m.value(s)
+}
+
+// Metrics are the metrics Compare reports, in display order.
+var Metrics = []Metric{
+	{Name: "ops", Label: "ops (M/s)", Scale: 1_000_000, HigherIsBetter: true,
+		value: func(s Sample) (float64, bool) { return s.OpsPerSec, s.Paused == 0 }},
+	{Name: "temp", Label: "temperature (°C)", Scale: 1,
+		value: func(s Sample) (float64, bool) { return s.Stats.Temperature, s.Stats.Temperature > 0 }},
+	{Name: "freq", Label: "frequency (MHz)", Scale: 1, HigherIsBetter: true,
+		value: func(s Sample) (float64, bool) { return float64(s.Stats.CPUFreqCur), s.Stats.CPUFreqMax > 0 }},
+	{Name: "power", Label: "package power (W)", Scale: 1,
+		value: func(s Sample) (float64, bool) { return s.PackageWatts, s.PackageWatts > 0 }},
+}
+
+// LookupMetric returns the metric with the given name.
+func LookupMetric(name string) (Metric, bool) {
+	for _, m := range Metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// Delta is the change of one metric between a baseline run and another.
+type Delta struct {
+	Metric      Metric
+	Base, Other Stat
+	Change      float64 // Other.Avg - Base.Avg
+	ChangePct   float64 // Change in percent of Base.Avg
+	T           float64 // Welch's t statistic of the change
+	Hint        string  // Significance hint, see Significance
+}
+
+// Significance hints, from Welch's t statistic of the per-sample values.
+// Samples of a run are not independent, so these are hints, not p-values.
+const (
+	Significant = "significant" // |t| >= 3
+	Likely      = "likely"      // |t| >= 2
+	Noise       = "within noise"
+	TooFew      = "too few samples"
+)
+
+// Compare returns the change of every metric that both runs measured,
+// using their steady-state samples.
+func Compare(base, other Report) []Delta {
+	var deltas []Delta
+	for _, m := range Metrics {
+		a, b := metricValues(base.Samples, m), metricValues(other.Samples, m)
+		if len(a) == 0 || len(b) == 0 {
+			continue
+		}
+		d := Delta{Metric: m}
+		for _, v := range a {
+			d.Base.add(v)
+		}
+		for _, v := range b {
+			d.Other.add(v)
+		}
+		d.Change = d.Other.Avg - d.Base.Avg
+		if d.Base.Avg != 0 {
+			d.ChangePct = d.Change / d.Base.Avg * 100
+		}
+		d.T, d.Hint = welch(a, b)
+		deltas = append(deltas, d)
+	}
+	return deltas
+}
+
+// metricValues returns the values of m in samples that have it.
+func metricValues(samples []Sample, m Metric) []float64 {
+	var values []float64
+	for _, s := range samples {
+		if v, ok := m.Value(s); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// welch returns Welch's t statistic for the difference of the means of
+// a and b, and its significance hint.
+func welch(a, b []float64) (float64, string) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, TooFew
+	}
+	ma, va := meanVar(a)
+	mb, vb := meanVar(b)
+	se := math.Sqrt(va/float64(len(a)) + vb/float64(len(b)))
+	if se == 0 {
+		if ma == mb {
+			return 0, Noise
+		}
+		return math.Copysign(math.Inf(1), mb-ma), Significant
+	}
+	t := (mb - ma) / se
+	switch {
+	case math.Abs(t) >= 3:
+		return t, Significant
+	case math.Abs(t) >= 2:
+		return t, Likely
+	}
+	return t, Noise
+}
+
+// meanVar returns the mean and sample variance of values.
+func meanVar(values []float64) (mean, variance float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance / float64(len(values)-1)
+}
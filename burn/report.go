@@ -0,0 +1,246 @@
+package burn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"goburn/hardware"
+)
+
+// Report is the outcome of a burn: its configuration, every sample and
+// a summary. It marshals to JSON with explicit units in field names.
+type Report struct {
+	Start     time.Time              `json:"start"`
+	End       time.Time              `json:"end"`
+	Duration  time.Duration          `json:"duration_ns"`  // Configured length, 0 if unlimited
+	Warmup    time.Duration          `json:"warmup_ns"`    // Initial part left out of the summary
+	Steady    bool                   `json:"steady"`       // Whether ops/s and temperature plateaued
+	SteadyAt  time.Duration          `json:"steady_at_ns"` // When they did, if Steady
+	Workers   int                    `json:"workers"`      // Initial worker count
+	Binding   Binding                `json:"binding"`      // How workers and memory were pinned
+	TotalOps  uint64                 `json:"total_ops"`    // Operations over the whole run
+	Inventory hardware.Inventory     `json:"inventory"`    // Machine the run happened on
+	Power     hardware.PowerSettings `json:"power"`        // Governor, EPP and turbo at start
+	Cgroup    hardware.CgroupLimits  `json:"cgroup"`
+	Summary   Summary                `json:"summary"`
+	Samples   []Sample               `json:"samples"`
+	Markers   []Marker               `json:"markers,omitempty"` // Labelled points in time, oldest first
+}
+
+// Marker is a labelled point in time of a run, such as when the case was
+// opened or the fan curve changed.
+type Marker struct {
+	Elapsed time.Duration `json:"elapsed_ns"` // Time since the burn started
+	Label   string        `json:"label"`
+	Sample  Sample        `json:"sample"` // Last sample before the marker, zero if there was none
+}
+
+// Summary aggregates the steady-state samples of a run, those taken
+// after the warmup phase. Metrics that were never available are left as
+// zero Stats.
+type Summary struct {
+	OpsPerSec   Stat          `json:"ops_per_sec"`
+	CPUFreqMHz  Stat          `json:"cpu_freq_mhz"`
+	Temperature Stat          `json:"temperature_c"`
+	FanRPM      Stat          `json:"fan_rpm"`
+	Power       Stat          `json:"package_watts"`
+	Throttled   time.Duration `json:"throttled_ns"` // Total cgroup throttled time
+	Paused      time.Duration `json:"paused_ns"`    // Total time the workers were paused
+
+	Sockets []SocketSummary `json:"sockets,omitempty"` // Per physical package, nil with a single one
+}
+
+// SocketSummary aggregates the samples of one physical package (socket).
+type SocketSummary struct {
+	Package     int  `json:"package"`
+	OpsPerSec   Stat `json:"ops_per_sec"`
+	CPUFreqMHz  Stat `json:"cpu_freq_mhz"`
+	Temperature Stat `json:"temperature_c"`
+	Power       Stat `json:"package_watts"`
+}
+
+// Stat holds the range and mean of a metric.
+type Stat struct {
+	Min   float64 `json:"min"`
+	Avg   float64 `json:"avg"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"` // Number of samples with the metric available
+}
+
+// add includes v in the statistic.
+func (s *Stat) add(v float64) {
+	if s.Count == 0 || v < s.Min {
+		s.Min = v
+	}
+	if s.Count == 0 || v > s.Max {
+		s.Max = v
+	}
+	s.Avg += (v - s.Avg) / float64(s.Count+1)
+	s.Count++
+}
+
+// Summarize computes the summary of a sequence of samples. Warmup samples
+// are left out. Samples taken while the workers were paused are left out
+// of the ops statistic, since they do not measure the machine's throughput.
+func Summarize(samples []Sample) Summary {
+	var sum Summary
+	for _, s := range samples {
+		if s.Warmup {
+			continue
+		}
+		if s.Paused == 0 {
+			sum.OpsPerSec.add(s.OpsPerSec)
+		}
+		sum.Paused += s.Paused
+		if s.Stats.CPUFreqMax > 0 {
+			sum.CPUFreqMHz.add(float64(s.Stats.CPUFreqCur))
+		}
+		if s.Stats.Temperature > 0 {
+			sum.Temperature.add(s.Stats.Temperature)
+		}
+		if avg, ok := avgFanRPM(s.Stats.FanRPMs); ok {
+			sum.FanRPM.add(avg)
+		}
+		if s.PackageWatts > 0 {
+			sum.Power.add(s.PackageWatts)
+		}
+		sum.Throttled += s.Throttled
+		sum.addSockets(s)
+	}
+	return sum
+}
+
+// addSockets includes the per-socket part of s in the summary.
+func (sum *Summary) addSockets(s Sample) {
+	for _, sock := range s.Sockets {
+		i := slices.IndexFunc(sum.Sockets, func(ss SocketSummary) bool { return ss.Package == sock.Package })
+		if i < 0 {
+			i = len(sum.Sockets)
+			sum.Sockets = append(sum.Sockets, SocketSummary{Package: sock.Package})
+		}
+		if s.Paused == 0 && sock.OpsPerSec > 0 {
+			sum.Sockets[i].OpsPerSec.add(sock.OpsPerSec)
+		}
+		if sock.CPUFreqMHz > 0 {
+			sum.Sockets[i].CPUFreqMHz.add(float64(sock.CPUFreqMHz))
+		}
+		if sock.Temperature > 0 {
+			sum.Sockets[i].Temperature.add(sock.Temperature)
+		}
+		if sock.PackageWatts > 0 {
+			sum.Sockets[i].Power.add(sock.PackageWatts)
+		}
+	}
+}
+
+// avgFanRPM averages fan speeds. Returns false if there are no fans.
+func avgFanRPM(fans []int) (float64, bool) {
+	if len(fans) == 0 {
+		return 0, false
+	}
+	total := 0
+	for _, rpm := range fans {
+		total += rpm
+	}
+	return float64(total) / float64(len(fans)), true
+}
+
+// Recorder accumulates samples into a Report.
+// It is not safe for concurrent use.
+type Recorder struct {
+	report Report
+}
+
+// NewRecorder starts a report from header, which describes the run:
+// its Start time, initial Workers and the machine it runs on. Samples,
+// totals and the summary are filled in by the recorder.
+func NewRecorder(header Report) *Recorder {
+	header.Samples = nil
+	header.TotalOps = 0
+	return &Recorder{report: header}
+}
+
+// Add appends a sample to the report.
+func (r *Recorder) Add(s Sample) {
+	r.report.Samples = append(r.report.Samples, s)
+	r.report.TotalOps += s.Ops
+}
+
+// Mark records a marker labelled label at elapsed time at, with the
+// metrics of the last sample added, and returns it.
+func (r *Recorder) Mark(at time.Duration, label string) Marker {
+	m := Marker{Elapsed: at, Label: label}
+	if n := len(r.report.Samples); n > 0 {
+		m.Sample = r.report.Samples[n-1]
+	}
+	r.report.Markers = append(r.report.Markers, m)
+	return m
+}
+
+// SetSteady records that the run reached steady state at elapsed time at.
+func (r *Recorder) SetSteady(at time.Duration) {
+	r.report.Steady = true
+	r.report.SteadyAt = at
+}
+
+// SetWarmup extends the warmup phase to the first d of the run, tagging
+// the samples recorded so far that cover any of it.
+func (r *Recorder) SetWarmup(d time.Duration) {
+	r.report.Warmup = d
+	for i, s := range r.report.Samples {
+		if s.Elapsed-s.Interval < d {
+			r.report.Samples[i].Warmup = true
+		}
+	}
+}
+
+// SetDuration changes the configured length of the run, such as when it
+// is extended from the TUI. Zero means unlimited.
+func (r *Recorder) SetDuration(d time.Duration) {
+	r.report.Duration = d
+}
+
+// Report finalizes and returns the report, with End set to end.
+func (r *Recorder) Report(end time.Time) Report {
+	report := r.report
+	report.End = end
+	report.Samples = append([]Sample(nil), r.report.Samples...)
+	report.Markers = append([]Marker(nil), r.report.Markers...)
+	report.Summary = Summarize(report.Samples)
+	return report
+}
+
+// WriteFile saves the report as indented JSON.
+func (r Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ReadReport loads a report saved by WriteFile.
+func ReadReport(path string) (Report, error) {
+	var r Report
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("%s: %w", path, err)
+	}
+	// Reports written before the binding was recorded have only the
+	// placement, as a top-level field
+	if r.Binding.Placement == "" {
+		var legacy struct {
+			Placement hardware.Placement `json:"placement"`
+		}
+		if json.Unmarshal(data, &legacy) == nil {
+			r.Binding.Placement = legacy.Placement
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,165 @@
+package burn
+
+import (
+	"sync/atomic"
+	"time"
+
+	"goburn/hardware"
+	"goburn/worker"
+)
+
+// Sample is one measurement of throughput and hardware state.
+type Sample struct {
+	Elapsed   time.Duration  `json:"elapsed_ns"`          // Time since the burn started
+	Interval  time.Duration  `json:"interval_ns"`         // Time since the previous sample
+	Ops       uint64         `json:"ops"`                 // Operations since the previous sample
+	OpsPerSec float64        `json:"ops_per_sec"`         // Ops normalized to one second
+	Workers   int            `json:"workers"`             // Active workers when sampled
+	Throttled time.Duration  `json:"throttled_ns"`        // Cgroup throttled time since the previous sample
+	Paused    time.Duration  `json:"paused_ns,omitempty"` // Time the pool was paused since the previous sample
+	Warmup    bool           `json:"warmup,omitempty"`    // Taken during the warmup phase
+	Stats     hardware.Stats `json:"hardware"`
+
+	Utilization     float64   `json:"utilization_pct"` // Busy time of all CPUs since the previous sample
+	CoreIDs         []int     `json:"-"`               // CPUs of CoreUtilization: those of Stats.CoreTimes with a previous sample
+	CoreUtilization []float64 `json:"-"`               // Busy time of each CPU in CoreIDs
+	PackageWatts    float64   `json:"package_watts"`   // RAPL package power, 0 if unavailable
+
+	// Sockets splits the sample by physical package, nil on machines
+	// with a single one
+	Sockets []SocketSample `json:"sockets,omitempty"`
+}
+
+// SocketSample is the part of a sample on one physical package (socket).
+type SocketSample struct {
+	Package      int     `json:"package"`       // physical_package_id
+	OpsPerSec    float64 `json:"ops_per_sec"`   // Ops of the workers pinned to the package, 0 if unpinned
+	CPUFreqMHz   int     `json:"cpu_freq_mhz"`  // Mean frequency of the package's CPUs, 0 if unavailable
+	Temperature  float64 `json:"temperature_c"` // Package temperature, 0 if unavailable
+	PackageWatts float64 `json:"package_watts"` // RAPL power of the package, 0 if unavailable
+}
+
+// Sampler turns the pool's cumulative counter into per-interval samples.
+// It is not safe for concurrent use.
+type Sampler struct {
+	pool          *worker.Pool
+	start         time.Time
+	warmup        time.Duration
+	lastTime      time.Time
+	lastCounter   uint64
+	lastThrottled int64
+	lastPaused    time.Duration
+	lastEnergy    int64
+	lastCPUTime   hardware.CPUTime
+	lastCoreTimes map[int]hardware.CPUTime
+	lastCPUOps    map[int]uint64
+	lastEnergies  map[int]int64 // Energy of every package by ID
+	packageOf     map[int]int   // Physical package of every logical CPU, read once
+}
+
+// NewSampler creates a sampler measuring pool from start onwards.
+func NewSampler(pool *worker.Pool, start time.Time) *Sampler {
+	return &Sampler{
+		pool:          pool,
+		start:         start,
+		lastTime:      start,
+		lastThrottled: -1,
+		lastEnergy:    -1,
+		packageOf:     hardware.GetTopology().PackageOf(),
+	}
+}
+
+// SetWarmup tags the samples covering any of the first d of the run as
+// warmup samples.
+func (s *Sampler) SetWarmup(d time.Duration) {
+	s.warmup = d
+}
+
+// Sample reads the counter and hardware stats and returns the change
+// since the previous call (or since start for the first call).
+func (s *Sampler) Sample() Sample {
+	now := time.Now()
+	c := atomic.LoadUint64(s.pool.GetCounter())
+	stats := hardware.GetForPackages(s.packageOf)
+
+	sample := Sample{
+		Elapsed:  now.Sub(s.start),
+		Interval: now.Sub(s.lastTime),
+		Ops:      c - s.lastCounter,
+		Workers:  s.pool.GetActiveCount(),
+		Stats:    stats,
+		Warmup:   s.lastTime.Sub(s.start) < s.warmup,
+	}
+	paused := s.pool.PausedTime()
+	sample.Paused = paused - s.lastPaused
+	s.lastPaused = paused
+	if dt := sample.Interval.Seconds(); dt > 0 {
+		sample.OpsPerSec = float64(sample.Ops) / dt
+	}
+	if s.lastThrottled >= 0 && stats.ThrottledUSec > s.lastThrottled {
+		sample.Throttled = time.Duration(stats.ThrottledUSec-s.lastThrottled) * time.Microsecond
+	}
+	// Energy counters wrap around, skip the interval where they do
+	if dt := sample.Interval.Seconds(); dt > 0 && s.lastEnergy >= 0 && stats.EnergyUJ >= s.lastEnergy {
+		sample.PackageWatts = float64(stats.EnergyUJ-s.lastEnergy) / 1_000_000 / dt
+	}
+	// CPU time is cumulative since boot, so the first sample has no baseline
+	if s.lastCPUTime.Total > 0 {
+		sample.Utilization = stats.CPUTime.Utilization(s.lastCPUTime)
+	}
+	if len(stats.CoreTimes) > 0 {
+		cores := make(map[int]hardware.CPUTime, len(stats.CoreTimes))
+		for _, t := range stats.CoreTimes {
+			// Nor has a CPU that was offline at the previous sample
+			if prev, ok := s.lastCoreTimes[t.ID]; ok {
+				sample.CoreIDs = append(sample.CoreIDs, t.ID)
+				sample.CoreUtilization = append(sample.CoreUtilization, t.Utilization(prev))
+			}
+			cores[t.ID] = t
+		}
+		s.lastCoreTimes = cores
+	}
+
+	sample.Sockets = s.sockets(stats, sample.Interval)
+
+	s.lastTime = now
+	s.lastCounter = c
+	s.lastThrottled = stats.ThrottledUSec
+	s.lastEnergy = stats.EnergyUJ
+	s.lastCPUTime = stats.CPUTime
+	return sample
+}
+
+// sockets splits the throughput of pinned workers and the package
+// readings by physical package. Returns nil on single-package machines.
+func (s *Sampler) sockets(stats hardware.Stats, interval time.Duration) []SocketSample {
+	if len(stats.Packages) == 0 {
+		return nil
+	}
+	dt := interval.Seconds()
+	sockets := make([]SocketSample, len(stats.Packages))
+	index := make(map[int]int, len(stats.Packages))
+	energies := make(map[int]int64, len(stats.Packages))
+	for i, p := range stats.Packages {
+		sockets[i] = SocketSample{Package: p.ID, CPUFreqMHz: p.CPUFreqCur, Temperature: p.Temperature}
+		index[p.ID] = i
+		// Energy counters wrap around, skip the interval where they do
+		if last, ok := s.lastEnergies[p.ID]; ok && dt > 0 && last >= 0 && p.EnergyUJ >= last {
+			sockets[i].PackageWatts = float64(p.EnergyUJ-last) / 1_000_000 / dt
+		}
+		energies[p.ID] = p.EnergyUJ
+	}
+	s.lastEnergies = energies
+
+	cpuOps := s.pool.CPUOps()
+	if dt > 0 && s.lastCPUOps != nil {
+		for cpu, ops := range cpuOps {
+			pkg, ok := s.packageOf[cpu]
+			if i, found := index[pkg]; ok && found {
+				sockets[i].OpsPerSec += float64(ops-s.lastCPUOps[cpu]) / dt
+			}
+		}
+	}
+	s.lastCPUOps = cpuOps
+	return sockets
+}
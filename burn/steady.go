@@ -0,0 +1,189 @@
+package burn
+
+import (
+	"math"
+	"time"
+)
+
+// Default limits of a SteadyDetector.
+const (
+	DefaultSteadyWindow = time.Minute
+	DefaultMaxTempSlope = 1.0 // °C per minute
+	DefaultMaxOpsSlope  = 2.0 // Percent of the window's mean per minute
+)
+
+// SteadyDetector finds when a run reaches steady state: when the
+// least-squares slopes of temperature and ops/s over a sliding window both
+// stay within limits. Temperature is ignored on machines without a sensor.
+// Once reached, steady state is kept for the rest of the run.
+// It is not safe for concurrent use.
+type SteadyDetector struct {
+	Window       time.Duration // Span of samples tested
+	MaxTempSlope float64       // Highest temperature slope, in °C per minute
+	MaxOpsSlope  float64       // Highest ops/s slope, in percent of the mean per minute
+
+	samples  []Sample
+	since    time.Duration // Start of the samples without a pause
+	steady   bool
+	steadyAt time.Duration
+}
+
+// NewSteadyDetector creates a detector with the default limits.
+func NewSteadyDetector() *SteadyDetector {
+	return &SteadyDetector{
+		Window:       DefaultSteadyWindow,
+		MaxTempSlope: DefaultMaxTempSlope,
+		MaxOpsSlope:  DefaultMaxOpsSlope,
+	}
+}
+
+// Add tests the window ending with s. It returns true if s brought the
+// run to steady state.
+func (d *SteadyDetector) Add(s Sample) bool {
+	if d.steady {
+		return false
+	}
+	// A pause breaks the trend, start over after it
+	if s.Paused > 0 {
+		d.samples = d.samples[:0]
+		return false
+	}
+	if len(d.samples) == 0 {
+		d.since = s.Elapsed - s.Interval
+	}
+	d.samples = append(d.samples, s)
+
+	// Keep the samples that end within the window
+	first := 0
+	for first < len(d.samples) && d.samples[first].Elapsed <= s.Elapsed-d.Window {
+		first++
+	}
+	d.samples = d.samples[first:]
+	if s.Elapsed-d.since < d.Window || len(d.samples) < 3 {
+		return false
+	}
+
+	var ops, temp []point
+	for _, w := range d.samples {
+		ops = append(ops, point{w.Elapsed.Minutes(), w.OpsPerSec})
+		if w.Stats.Temperature > 0 {
+			temp = append(temp, point{w.Elapsed.Minutes(), w.Stats.Temperature})
+		}
+	}
+	opsSlope, opsMean := slope(ops)
+	if opsMean <= 0 || math.Abs(opsSlope)/opsMean*100 > d.MaxOpsSlope {
+		return false
+	}
+	if len(temp) >= 3 {
+		if tempSlope, _ := slope(temp); math.Abs(tempSlope) > d.MaxTempSlope {
+			return false
+		}
+	}
+
+	d.steady = true
+	d.steadyAt = max(s.Elapsed-d.Window, 0)
+	return true
+}
+
+// Steady returns the elapsed time steady state began: the start of the
+// first window that passed. Returns false if it has not been reached.
+func (d *SteadyDetector) Steady() (time.Duration, bool) {
+	return d.steadyAt, d.steady
+}
+
+// SteadyTracker applies steady state to a run, the same way in line mode
+// and in the TUI: it records in the report when steady state began and,
+// for runs that wait for it, extends the warmup to the sample that
+// detected it and ends the run UntilSteady after that sample. Samples of
+// the detection window are still settling, so only later ones are
+// measured. It is not safe for concurrent use.
+type SteadyTracker struct {
+	detector    *SteadyDetector
+	sampler     *Sampler
+	recorder    *Recorder
+	untilSteady time.Duration
+	warmup      time.Duration
+	end         time.Duration // When the run ends, 0 until steady state
+}
+
+// NewSteadyTracker creates a tracker with the default detector limits for
+// a run with the given warmup. untilSteady is Config.UntilSteady: 0 only
+// records steady state.
+func NewSteadyTracker(sampler *Sampler, recorder *Recorder, warmup, untilSteady time.Duration) *SteadyTracker {
+	return &SteadyTracker{
+		detector:    NewSteadyDetector(),
+		sampler:     sampler,
+		recorder:    recorder,
+		untilSteady: untilSteady,
+		warmup:      warmup,
+	}
+}
+
+// Add tests s, a sample the recorder has already added.
+func (t *SteadyTracker) Add(s Sample) {
+	if !t.detector.Add(s) {
+		return
+	}
+	at, _ := t.detector.Steady()
+	t.recorder.SetSteady(at)
+	if t.untilSteady == 0 {
+		return
+	}
+	if s.Elapsed > t.warmup {
+		t.warmup = s.Elapsed
+		t.recorder.SetWarmup(t.warmup)
+		t.sampler.SetWarmup(t.warmup)
+	}
+	t.end = max(s.Elapsed, t.warmup) + t.untilSteady
+}
+
+// Steady returns the elapsed time steady state began. Returns false if
+// it has not been reached.
+func (t *SteadyTracker) Steady() (time.Duration, bool) {
+	return t.detector.Steady()
+}
+
+// Warmup returns the warmup in effect, extended once steady state is
+// detected in a run that waits for it.
+func (t *SteadyTracker) Warmup() time.Duration {
+	return t.warmup
+}
+
+// End returns the elapsed time at which a run that waits for steady state
+// ends. Returns false before steady state, or if the run does not wait.
+func (t *SteadyTracker) End() (time.Duration, bool) {
+	return t.end, t.end > 0
+}
+
+// Done reports whether a run that waits for steady state has measured
+// for UntilSteady by elapsed time elapsed.
+func (t *SteadyTracker) Done(elapsed time.Duration) bool {
+	end, ok := t.End()
+	return ok && elapsed >= end
+}
+
+// point is one value of a series to fit.
+type point struct {
+	x, y float64
+}
+
+// slope returns the least-squares slope of points and the mean of y.
+func slope(points []point) (slope, mean float64) {
+	var sx, sy float64
+	for _, p := range points {
+		sx += p.x
+		sy += p.y
+	}
+	n := float64(len(points))
+	mx, my := sx/n, sy/n
+
+	var sxy, sxx float64
+	for _, p := range points {
+		sxy += (p.x - mx) * (p.y - my)
+		sxx += (p.x - mx) * (p.x - mx)
+	}
+	if sxx == 0 {
+		return 0, my
+	}
+	return sxy / sxx, my
+}
@@ -0,0 +1,220 @@
+package burn
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"goburn/hardware"
+	"goburn/worker"
+)
+
+// SweepConfig describes a scaling sweep. The zero value steps from one
+// worker to DefaultWorkers, measuring each step for ten seconds after a
+// five-second warmup.
+type SweepConfig struct {
+	Steps    []int         // Worker counts in order, nil for 1 through DefaultWorkers
+	Warmup   time.Duration // Settling time after each change, 0 for five seconds
+	Hold     time.Duration // Measured time of each step, 0 for ten seconds
+	Interval time.Duration // Sample interval, 0 for one second
+
+	// Binding pins workers to CPUs in the order of its placement, so
+	// that each step adds the next CPU of the order. The zero value
+	// leaves placement to the scheduler.
+	Binding Binding
+
+	// PoolOptions are passed to worker.New.
+	PoolOptions []worker.Option
+
+	// OnStart is called with the report header before the workers start.
+	OnStart func(Report)
+
+	// OnSample is called from the RunSweep goroutine after every sample.
+	OnSample func(Sample)
+
+	// OnStep is called from the RunSweep goroutine after every step.
+	OnStep func(SweepStep)
+}
+
+// SweepResult is the outcome of a sweep: the steps and the recorded run,
+// with a marker where each step starts and its warmup samples tagged.
+type SweepResult struct {
+	Steps  []SweepStep `json:"steps"`
+	Report Report      `json:"report"`
+}
+
+// SweepStep is the measurement of one worker count.
+type SweepStep struct {
+	Workers int     `json:"workers"`
+	Summary Summary `json:"summary"` // Samples of the step after its warmup
+
+	// OpsPerWorker is the mean ops/s divided by Workers.
+	OpsPerWorker float64 `json:"ops_per_worker"`
+	// Speedup is the mean ops/s over that of the first step.
+	Speedup float64 `json:"speedup"`
+	// Efficiency is OpsPerWorker in percent of that of the first step:
+	// 100 while throughput scales linearly with workers.
+	Efficiency float64 `json:"efficiency_pct"`
+}
+
+// DefaultSweepSteps returns the worker counts of a sweep without Steps:
+// every count from one to DefaultWorkers.
+func DefaultSweepSteps(limits hardware.CgroupLimits) []int {
+	steps := make([]int, DefaultWorkers(limits))
+	for i := range steps {
+		steps[i] = i + 1
+	}
+	return steps
+}
+
+// RunSweep runs the pool at each worker count of cfg.Steps in turn and
+// measures every step. If ctx is cancelled, RunSweep stops the workers
+// and returns the steps completed so far together with ctx.Err().
+func RunSweep(ctx context.Context, cfg SweepConfig) (SweepResult, error) {
+	if cfg.Warmup < 0 || cfg.Hold < 0 || cfg.Interval < 0 {
+		return SweepResult{}, errors.New("burn: negative sweep time")
+	}
+	for _, n := range cfg.Steps {
+		if n <= 0 {
+			return SweepResult{}, fmt.Errorf("burn: invalid sweep step of %d workers", n)
+		}
+	}
+	if cfg.Warmup == 0 {
+		cfg.Warmup = 5 * time.Second
+	}
+	if cfg.Hold == 0 {
+		cfg.Hold = 10 * time.Second
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Second
+	}
+	poolOptions, err := cfg.Binding.Options(cfg.PoolOptions)
+	if err != nil {
+		return SweepResult{}, err
+	}
+	limits := hardware.GetCgroupLimits()
+	if len(cfg.Steps) == 0 {
+		cfg.Steps = DefaultSweepSteps(limits)
+	}
+
+	header := Report{
+		Duration:  time.Duration(len(cfg.Steps)) * (cfg.Warmup + cfg.Hold),
+		Warmup:    cfg.Warmup,
+		Workers:   cfg.Steps[0],
+		Binding:   cfg.Binding,
+		Inventory: hardware.GetInventory(),
+		Power:     hardware.GetPowerSettings(),
+		Cgroup:    limits,
+	}
+	if cfg.OnStart != nil {
+		cfg.OnStart(header)
+	}
+
+	var counter uint64
+	header.Start = time.Now()
+	pool := worker.New(ctx, &counter, cfg.Steps[0], poolOptions...)
+	defer pool.Stop()
+
+	sampler := NewSampler(pool, header.Start)
+	recorder := NewRecorder(header)
+	recorder.Mark(0, stepLabel(cfg.Steps[0]))
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	var result SweepResult
+	var stepStart time.Duration
+	var samples []Sample
+	for len(result.Steps) < len(cfg.Steps) {
+		select {
+		case <-ctx.Done():
+			result.Report = recorder.Report(time.Now())
+			return result, ctx.Err()
+		case <-ticker.C:
+		}
+
+		// Samples that began before the step settled are its warmup
+		sample := sampler.Sample()
+		sample.Warmup = sample.Elapsed-sample.Interval < stepStart+cfg.Warmup
+		recorder.Add(sample)
+		samples = append(samples, sample)
+		if cfg.OnSample != nil {
+			cfg.OnSample(sample)
+		}
+		if sample.Elapsed < stepStart+cfg.Warmup+cfg.Hold {
+			continue
+		}
+
+		step := newSweepStep(cfg.Steps[len(result.Steps)], samples, result.Steps)
+		result.Steps = append(result.Steps, step)
+		if cfg.OnStep != nil {
+			cfg.OnStep(step)
+		}
+		if next := len(result.Steps); next < len(cfg.Steps) {
+			pool.SetWorkers(cfg.Steps[next])
+			stepStart, samples = sample.Elapsed, nil
+			recorder.Mark(stepStart, stepLabel(cfg.Steps[next]))
+		}
+	}
+	result.Report = recorder.Report(time.Now())
+	return result, nil
+}
+
+// stepLabel is the marker label at the start of a step.
+func stepLabel(workers int) string {
+	return fmt.Sprintf("%d workers", workers)
+}
+
+// newSweepStep summarizes the samples of a step, comparing its throughput
+// to the first of the previous steps.
+func newSweepStep(workers int, samples []Sample, previous []SweepStep) SweepStep {
+	step := SweepStep{Workers: workers, Summary: Summarize(samples)}
+	step.OpsPerWorker = step.Summary.OpsPerSec.Avg / float64(workers)
+
+	first := step
+	if len(previous) > 0 {
+		first = previous[0]
+	}
+	if first.Summary.OpsPerSec.Avg > 0 {
+		step.Speedup = step.Summary.OpsPerSec.Avg / first.Summary.OpsPerSec.Avg
+	}
+	if first.OpsPerWorker > 0 {
+		step.Efficiency = step.OpsPerWorker / first.OpsPerWorker * 100
+	}
+	return step
+}
+
+// WriteCSV writes one row per step, with a header row. Metrics a machine
+// does not have are left empty.
+func (r SweepResult) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"workers", "ops_per_sec", "ops_per_worker", "speedup", "efficiency_pct",
+		"temp_c", "cpu_mhz", "package_watts"})
+	for _, s := range r.Steps {
+		cw.Write([]string{
+			strconv.Itoa(s.Workers),
+			formatFloat(s.Summary.OpsPerSec, s.Summary.OpsPerSec.Avg, 0),
+			formatFloat(s.Summary.OpsPerSec, s.OpsPerWorker, 0),
+			formatFloat(s.Summary.OpsPerSec, s.Speedup, 3),
+			formatFloat(s.Summary.OpsPerSec, s.Efficiency, 1),
+			formatFloat(s.Summary.Temperature, s.Summary.Temperature.Avg, 1),
+			formatFloat(s.Summary.CPUFreqMHz, s.Summary.CPUFreqMHz.Avg, 0),
+			formatFloat(s.Summary.Power, s.Summary.Power.Avg, 1),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatFloat formats v with prec decimals, or returns an empty string if
+// stat, which v derives from, has no samples.
+func formatFloat(stat Stat, v float64, prec int) string {
+	if stat.Count == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
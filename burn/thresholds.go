@@ -0,0 +1,28 @@
+package burn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Thresholds are pass/fail limits checked against a run summary.
+// Zero fields are not checked.
+type Thresholds struct {
+	MaxTemp      float64 // Highest acceptable temperature in Celsius
+	MinOpsPerSec float64 // Lowest acceptable average ops per second
+}
+
+// Check returns an error describing every threshold the summary violates,
+// or nil if the run passed.
+func (t Thresholds) Check(s Summary) error {
+	var errs []error
+	if t.MaxTemp > 0 && s.Temperature.Count > 0 && s.Temperature.Max > t.MaxTemp {
+		errs = append(errs, fmt.Errorf("temperature reached %.1f°C, above the %.1f°C limit",
+			s.Temperature.Max, t.MaxTemp))
+	}
+	if t.MinOpsPerSec > 0 && s.OpsPerSec.Count > 0 && s.OpsPerSec.Avg < t.MinOpsPerSec {
+		errs = append(errs, fmt.Errorf("average throughput %.0fM ops/s, below the %.0fM ops/s limit",
+			s.OpsPerSec.Avg/1_000_000, t.MinOpsPerSec/1_000_000))
+	}
+	return errors.Join(errs...)
+}
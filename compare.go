@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goburn/burn"
+	"goburn/ui"
+)
+
+// compareCommand prints how recorded runs differ from the first one.
+func compareCommand(args []string) int {
+	names := make([]string, len(burn.Metrics))
+	for i, m := range burn.Metrics {
+		names[i] = m.Name
+	}
+	fs := newFlagSet("compare", "<baseline.json> <report.json>...", `Compare reports recorded with 'goburn run -report=<file>' against the
+first one, the baseline. For ops/s, temperature, frequency and package
+power, print the mean of each run after warmup and the change from the
+baseline, with a hint whether the change stands out from the
+sample-to-sample noise. Metrics a run did not measure are left out.`,
+		"  0    reports compared\n"+
+			"  1    a report could not be read\n"+
+			"  2    invalid flags or fewer than two reports\n")
+	chart := fs.String("chart", "", "Also plot this metric of every run over time: "+strings.Join(names, ", "))
+	if ok, code := parseFlags(fs, args); !ok {
+		return code
+	}
+	if *chart != "" {
+		if _, ok := burn.LookupMetric(*chart); !ok {
+			fmt.Fprintf(os.Stderr, "goburn compare: unknown metric %q (want %s)\n", *chart, strings.Join(names, ", "))
+			return exitUsage
+		}
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	files := make([]string, fs.NArg())
+	reports := make([]burn.Report, fs.NArg())
+	for i, path := range fs.Args() {
+		report, err := burn.ReadReport(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "goburn compare: %v\n", err)
+			return exitFailure
+		}
+		files[i], reports[i] = filepath.Base(path), report
+	}
+
+	ui.RunCompareMode(files, reports, *chart)
+	return exitOK
+}
@@ -0,0 +1,348 @@
+// Package config loads goburn settings and named presets from a config file.
+//
+// The file uses a small subset of TOML: comments, [preset.<name>] tables
+// and key = value pairs whose values are strings, numbers or booleans.
+// Keys are the same as the command-line flag names:
+//
+//	[preset.quick-smoke]
+//	description = "30 second sanity check"
+//	duration = "30s"
+//	workers = 2
+//	max-temp = 90
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"goburn/panel"
+	"goburn/worker"
+)
+
+// DefaultFile is the config file read when -config is not given.
+const DefaultFile = "goburn.toml"
+
+// Settings holds every option of a burn run.
+type Settings struct {
+	Duration    time.Duration // Test duration
+	Warmup      time.Duration // Initial part of the run left out of the summary
+	UntilSteady time.Duration // End this long after steady state, 0 to run for Duration
+	Interval    time.Duration // Sample interval
+	Workers     int           // Worker count, 0 for automatic
+	Workload    string        // Name of a worker.Workload
+	Placement   string        // One of PlacementNames
+	Nodes       []int         // NUMA nodes running workers, nil for all
+	MemNodes    []int         // NUMA nodes holding worker memory, nil for each worker's own
+	Graph       bool          // Use the interactive TUI
+	MaxTemp     float64       // Fail if temperature exceeds this (°C), 0 to disable
+	MinOps      float64       // Fail if average ops fall below this (M/s), 0 to disable
+	Report      string        // Write a JSON report to this path
+	Format      string        // Line mode output: text, json or logfmt
+	Panels      []string      // TUI panels in display order
+	Theme       string        // TUI color theme, one of ThemeNames
+}
+
+// ThemeNames lists the TUI color themes. "auto" is dark, or monochrome
+// when the NO_COLOR environment variable is set.
+var ThemeNames = []string{"auto", "dark", "light", "high-contrast", "monochrome"}
+
+// PlacementNames lists the worker placements: "none" leaves workers
+// unpinned, the others are the hardware.Placements.
+var PlacementNames = []string{"none", "spread", "pack", "node", "socket"}
+
+// DefaultPlacement is the placement used when none is specified.
+const DefaultPlacement = "none"
+
+// Defaults returns the settings used when nothing else is specified.
+func Defaults() Settings {
+	return Settings{
+		Duration:  50 * time.Second,
+		Interval:  time.Second,
+		Workload:  worker.DefaultWorkload,
+		Placement: DefaultPlacement,
+		Format:    "text",
+		Panels:    slices.Clone(panel.Defaults),
+		Theme:     "auto",
+	}
+}
+
+// Set assigns a setting by its key (the flag name) from its string form.
+func (s *Settings) Set(key, value string) error {
+	var err error
+	switch key {
+	case "duration":
+		s.Duration, err = time.ParseDuration(value)
+	case "warmup":
+		s.Warmup, err = time.ParseDuration(value)
+		if err == nil && s.Warmup < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	case "until-steady":
+		s.UntilSteady, err = time.ParseDuration(value)
+		if err == nil && s.UntilSteady < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	case "interval":
+		s.Interval, err = time.ParseDuration(value)
+		if err == nil && s.Interval < 10*time.Millisecond {
+			err = fmt.Errorf("must be at least 10ms")
+		}
+	case "workers":
+		s.Workers, err = strconv.Atoi(value)
+		if err == nil && s.Workers < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	case "workload":
+		if _, ok := worker.LookupWorkload(value); !ok {
+			err = fmt.Errorf("unknown workload %q", value)
+		}
+		s.Workload = value
+	case "placement":
+		if !slices.Contains(PlacementNames, value) {
+			err = fmt.Errorf("want %s", strings.Join(PlacementNames, ", "))
+		}
+		s.Placement = value
+	case "nodes":
+		s.Nodes, err = ParseNodes(value)
+	case "mem-nodes":
+		s.MemNodes, err = ParseNodes(value)
+	case "graph":
+		s.Graph, err = strconv.ParseBool(value)
+	case "max-temp":
+		s.MaxTemp, err = strconv.ParseFloat(value, 64)
+	case "min-ops":
+		s.MinOps, err = strconv.ParseFloat(value, 64)
+	case "report":
+		s.Report = value
+	case "format":
+		if value != "text" && value != "json" && value != "logfmt" {
+			err = fmt.Errorf("want text, json or logfmt")
+		}
+		s.Format = value
+	case "panels":
+		s.Panels, err = parsePanels(value)
+	case "theme":
+		if !slices.Contains(ThemeNames, value) {
+			err = fmt.Errorf("want %s", strings.Join(ThemeNames, ", "))
+		}
+		s.Theme = value
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return nil
+}
+
+// parsePanels parses a comma-separated list of panel names.
+func parsePanels(list string) ([]string, error) {
+	var panels []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(panel.Names, name) {
+			return nil, fmt.Errorf("unknown panel %q, want %s", name, strings.Join(panel.Names, ", "))
+		}
+		if !slices.Contains(panels, name) {
+			panels = append(panels, name)
+		}
+	}
+	return panels, nil
+}
+
+// ParseNodes parses a comma-separated list of NUMA node numbers, such as
+// the value of -nodes.
+// An empty list selects every node.
+func ParseNodes(list string) ([]int, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	var nodes []int
+	for _, field := range strings.Split(list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("want comma-separated node numbers")
+		}
+		if !slices.Contains(nodes, n) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
+
+// Preset is a named set of settings.
+type Preset struct {
+	Name        string
+	Description string
+	Source      string // File the preset was loaded from, or "built-in"
+	values      [][2]string
+}
+
+// Apply sets every key of the preset on s, in file order.
+func (p Preset) Apply(s *Settings) error {
+	for _, kv := range p.values {
+		if err := s.Set(kv[0], kv[1]); err != nil {
+			return fmt.Errorf("preset %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Values returns the preset's settings as "key = value" strings.
+func (p Preset) Values() []string {
+	out := make([]string, len(p.values))
+	for i, kv := range p.values {
+		out[i] = kv[0] + " = " + kv[1]
+	}
+	return out
+}
+
+// Config is a set of presets, including the built-in ones.
+type Config struct {
+	presets map[string]Preset
+}
+
+// builtinPresets are always available and may be overridden by the file.
+var builtinPresets = []Preset{
+	{
+		Name:        "quick-smoke",
+		Description: "30 second sanity check on every CPU",
+		values:      [][2]string{{"duration", "30s"}},
+	},
+	{
+		Name:        "24h-soak",
+		Description: "24 hour stability soak, fails above 95°C",
+		values:      [][2]string{{"duration", "24h"}, {"max-temp", "95"}, {"report", "soak.json"}},
+	},
+}
+
+// Load reads presets from path on top of the built-in ones. A missing
+// file is only an error if required is true.
+func Load(path string, required bool) (*Config, error) {
+	cfg := &Config{presets: make(map[string]Preset)}
+	for _, p := range builtinPresets {
+		p.Source = "built-in"
+		cfg.presets[p.Name] = p
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	presets, err := parse(f, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range presets {
+		cfg.presets[p.Name] = p
+	}
+	return cfg, nil
+}
+
+// Preset returns the named preset.
+func (c *Config) Preset(name string) (Preset, error) {
+	p, ok := c.presets[name]
+	if !ok {
+		return Preset{}, fmt.Errorf("unknown preset %q", name)
+	}
+	return p, nil
+}
+
+// Presets returns every preset sorted by name.
+func (c *Config) Presets() []Preset {
+	list := make([]Preset, 0, len(c.presets))
+	for _, p := range c.presets {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// parse reads preset tables from r. Values are validated against a
+// scratch Settings so mistakes are reported with their line number.
+func parse(r io.Reader, source string) ([]Preset, error) {
+	var presets []Preset
+	var current *Preset
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("%s:%d: %s", source, lineNo, fmt.Sprintf(format, args...))
+		}
+
+		if strings.HasPrefix(line, "[") {
+			name, ok := strings.CutPrefix(strings.Trim(line, "[]"), "preset.")
+			if !ok || name == "" || !strings.HasSuffix(line, "]") {
+				return nil, fail("expected [preset.<name>], got %s", line)
+			}
+			presets = append(presets, Preset{Name: unquote(name), Source: source})
+			current = &presets[len(presets)-1]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fail("expected key = value")
+		}
+		if current == nil {
+			return nil, fail("setting outside of a [preset.<name>] table")
+		}
+		key = strings.TrimSpace(key)
+		value = unquote(strings.TrimSpace(value))
+
+		if key == "description" {
+			current.Description = value
+			continue
+		}
+		var scratch Settings
+		if err := scratch.Set(key, value); err != nil {
+			return nil, fail("%v", err)
+		}
+		current.values = append(current.values, [2]string{key, value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+// stripComment removes a trailing # comment that is not inside quotes.
+func stripComment(line string) string {
+	inQuote := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case r == '#' && !inQuote:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquote removes surrounding double quotes from a TOML string.
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	}
+	return s
+}
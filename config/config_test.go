@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"goburn/hardware"
+	"goburn/ui"
+)
+
+func TestParsePresets(t *testing.T) {
+	input := `
+# comment line
+[preset.quick]
+description = "fast # not a comment"
+duration = "10s"   # trailing comment
+warmup = "2s"
+workers = 4
+placement = "spread"
+nodes = "1, 0,1"
+graph = true
+panels = "temp, ops,temp"
+
+[preset."with space"]
+max-temp = 85.5
+`
+	presets, err := parse(strings.NewReader(input), "test.toml")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(presets) != 2 {
+		t.Fatalf("got %d presets, want 2", len(presets))
+	}
+
+	quick := presets[0]
+	if quick.Name != "quick" || quick.Description != "fast # not a comment" {
+		t.Errorf("quick = %+v", quick)
+	}
+	s := Defaults()
+	if err := quick.Apply(&s); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if s.Duration != 10*time.Second || s.Warmup != 2*time.Second || s.Workers != 4 || s.Placement != "spread" || !slices.Equal(s.Nodes, []int{1, 0}) || !s.Graph || strings.Join(s.Panels, ",") != "temp,ops" {
+		t.Errorf("settings after quick = %+v", s)
+	}
+
+	if presets[1].Name != "with space" {
+		t.Errorf("second preset name = %q", presets[1].Name)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"duration = \"1s\"":             "outside",
+		"[other]\n":                     "expected [preset.<name>]",
+		"[preset.a]\nnonsense\n":        "expected key = value",
+		"[preset.a]\ncolour = \"red\"":  "unknown setting",
+		"[preset.a]\nworkers = many":    ":2: invalid workers",
+		"[preset.a]\nwarmup = \"-1s\"":  "must not be negative",
+		"[preset.a]\nuntil-steady = 5":  "invalid until-steady",
+		"[preset.a]\nworkload = \"x\"":  "unknown workload",
+		"[preset.a]\npanels = \"gpu\"":  "unknown panel",
+		"[preset.a]\nplacement = \"x\"": "invalid placement",
+		"[preset.a]\ntheme = \"pink\"":  "invalid theme",
+		"[preset.a]\nmem-nodes = \"a\"": "invalid mem-nodes",
+	}
+	for input, want := range cases {
+		_, err := parse(strings.NewReader(input), "test.toml")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parse(%q) error = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestPlacementNames(t *testing.T) {
+	for _, p := range hardware.Placements {
+		if !slices.Contains(PlacementNames, string(p)) {
+			t.Errorf("placement %q missing from PlacementNames", p)
+		}
+	}
+}
+
+func TestThemeNames(t *testing.T) {
+	for _, name := range ThemeNames {
+		if _, ok := ui.LookupTheme(name); !ok {
+			t.Errorf("theme %q has no definition", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	// A missing optional file still provides the built-in presets
+	cfg, err := Load(filepath.Join(dir, "missing.toml"), false)
+	if err != nil {
+		t.Fatalf("Load optional: %v", err)
+	}
+	if _, err := cfg.Preset("quick-smoke"); err != nil {
+		t.Errorf("built-in preset missing: %v", err)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.toml"), true); err == nil {
+		t.Error("Load of a missing required file succeeded")
+	}
+
+	// File presets override built-ins of the same name
+	path := filepath.Join(dir, "goburn.toml")
+	if err := os.WriteFile(path, []byte("[preset.quick-smoke]\nduration = \"5s\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(path, true)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	p, _ := cfg.Preset("quick-smoke")
+	if p.Source != path {
+		t.Errorf("quick-smoke source = %q, want %q", p.Source, path)
+	}
+	if _, err := cfg.Preset("nope"); err == nil {
+		t.Error("unknown preset found")
+	}
+}
@@ -0,0 +1,160 @@
+package hardware
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is the mount point of the unified (v2) cgroup hierarchy.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// CgroupLimits describes the CPU limits cgroup v2 imposes on this process.
+// Zero values mean the limit is not set or cgroup v2 is not available.
+type CgroupLimits struct {
+	Path          string  `json:"path"`           // Cgroup path relative to the v2 mount
+	CPUQuota      float64 `json:"cpu_quota"`      // CPUs allowed by cpu.max (quota / period)
+	EffectiveCPUs int     `json:"effective_cpus"` // Number of CPUs in cpuset.cpus.effective
+}
+
+// GetCgroupLimits reads the cgroup v2 CPU quota and cpuset of this process.
+// The quota is the tightest cpu.max found walking up to the root cgroup.
+func GetCgroupLimits() CgroupLimits {
+	path, ok := getCgroupPath()
+	if !ok {
+		return CgroupLimits{}
+	}
+
+	limits := CgroupLimits{Path: path}
+	for dir := path; ; dir = filepath.Dir(dir) {
+		quota := getCPUMax(filepath.Join(cgroupRoot, dir, "cpu.max"))
+		if quota > 0 && (limits.CPUQuota == 0 || quota < limits.CPUQuota) {
+			limits.CPUQuota = quota
+		}
+		if dir == "/" {
+			break
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(cgroupRoot, path, "cpuset.cpus.effective")); err == nil {
+		limits.EffectiveCPUs = len(parseCPUList(string(data)))
+	}
+
+	return limits
+}
+
+// CPULimit returns the number of workers that saturates the cgroup limits,
+// rounding a fractional quota up. Returns 0 if no limit applies.
+func (l CgroupLimits) CPULimit() int {
+	n := l.EffectiveCPUs
+	if l.CPUQuota > 0 {
+		q := int(math.Ceil(l.CPUQuota))
+		if n == 0 || q < n {
+			n = q
+		}
+	}
+	return n
+}
+
+// Limited reports whether any cgroup CPU limit applies.
+func (l CgroupLimits) Limited() bool {
+	return l.CPUQuota > 0 || l.EffectiveCPUs > 0
+}
+
+// getCgroupPath returns the cgroup v2 path of this process from /proc/self/cgroup.
+func getCgroupPath() (string, bool) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", false
+	}
+
+	// The unified hierarchy is listed as "0::<path>"
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			// cgroup.controllers only exists on a v2 mount
+			if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+				return "", false
+			}
+			return filepath.Clean("/" + path), true
+		}
+	}
+	return "", false
+}
+
+// getCPUMax reads a cpu.max file ("<quota> <period>" or "max <period>").
+// Returns the quota in CPUs, or 0 if unlimited or unavailable.
+func getCPUMax(path string) float64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+	period, err := strconv.Atoi(fields[1])
+	if err != nil || period <= 0 {
+		return 0
+	}
+	return float64(quota) / float64(period)
+}
+
+// getCgroupThrottled reads the cumulative throttled time from cpu.stat.
+// Returns microseconds, or -1 if cgroup v2 is not available.
+func getCgroupThrottled() int64 {
+	path, ok := getCgroupPath()
+	if !ok {
+		return -1
+	}
+	f, err := os.Open(filepath.Join(cgroupRoot, path, "cpu.stat"))
+	if err != nil {
+		return -1
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		if key == "throttled_usec" {
+			usec, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return -1
+			}
+			return usec
+		}
+	}
+	return -1
+}
+
+// parseCPUList parses a kernel CPU list such as "0-3,8,10-11".
+// Returns the listed CPU numbers in order.
+func parseCPUList(list string) []int {
+	var cpus []int
+	for _, part := range strings.Split(strings.TrimSpace(list), ",") {
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			continue
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				continue
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus
+}
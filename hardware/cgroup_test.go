@@ -0,0 +1,53 @@
+package hardware
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetCPUMax(t *testing.T) {
+	cases := []struct {
+		content string
+		want    float64
+	}{
+		{"max 100000", 0},
+		{"200000 100000", 2},
+		{"50000 100000", 0.5},
+		{"150000 100000\n", 1.5},
+		{"100000 0", 0},
+		{"abc 100000", 0},
+		{"100000", 0},
+		{"", 0},
+	}
+	for _, tc := range cases {
+		root := t.TempDir()
+		writeFile(t, root, "cpu.max", tc.content)
+		if got := getCPUMax(filepath.Join(root, "cpu.max")); got != tc.want {
+			t.Errorf("getCPUMax(%q) = %v, want %v", tc.content, got, tc.want)
+		}
+	}
+	if got := getCPUMax(filepath.Join(t.TempDir(), "missing")); got != 0 {
+		t.Errorf("getCPUMax of a missing file = %v, want 0", got)
+	}
+}
+
+func TestParseCPUList(t *testing.T) {
+	cases := []struct {
+		list string
+		want []int
+	}{
+		{"0", []int{0}},
+		{"0-3", []int{0, 1, 2, 3}},
+		{"0-1,8,10-11\n", []int{0, 1, 8, 10, 11}},
+		{"4,2", []int{4, 2}},
+		{"0-1,,x,3", []int{0, 1, 3}},
+		{"2-x", nil},
+		{"", nil},
+	}
+	for _, tc := range cases {
+		if got := parseCPUList(tc.list); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseCPUList(%q) = %v, want %v", tc.list, got, tc.want)
+		}
+	}
+}
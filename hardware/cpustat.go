@@ -0,0 +1,96 @@
+package hardware
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procStat is the kernel's cumulative CPU time accounting.
+const procStat = "/proc/stat"
+
+// CPUTime is the cumulative time one CPU, or all of them, spent busy,
+// in clock ticks since boot.
+type CPUTime struct {
+	ID    int    // CPU number, -1 for the sum of all CPUs
+	Busy  uint64 // Ticks not spent idle or waiting for I/O
+	Total uint64 // All ticks
+}
+
+// Utilization returns the busy percentage between prev and t,
+// or 0 if no time has passed.
+func (t CPUTime) Utilization(prev CPUTime) float64 {
+	if t.Total <= prev.Total || t.Busy < prev.Busy {
+		return 0
+	}
+	return float64(t.Busy-prev.Busy) / float64(t.Total-prev.Total) * 100
+}
+
+// getCPUTimes reads the time of all CPUs and of each online CPU.
+func getCPUTimes() (CPUTime, []CPUTime) {
+	return readCPUTimes(procStat)
+}
+
+// readCPUTimes parses the cpu lines of a /proc/stat file. The total has
+// ID -1 and a zero Total if the file cannot be read.
+func readCPUTimes(path string) (CPUTime, []CPUTime) {
+	all := CPUTime{ID: -1}
+	f, err := os.Open(path)
+	if err != nil {
+		return all, nil
+	}
+	defer f.Close()
+
+	var cores []CPUTime
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+
+		// user nice system idle iowait irq softirq steal; guest time is
+		// already included in user and nice
+		t := CPUTime{ID: -1}
+		for i, field := range fields[1:min(len(fields), 9)] {
+			ticks, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				continue
+			}
+			t.Total += ticks
+			if i != 3 && i != 4 {
+				t.Busy += ticks
+			}
+		}
+
+		if fields[0] == "cpu" {
+			all.Busy, all.Total = t.Busy, t.Total
+			continue
+		}
+		if id, err := strconv.Atoi(strings.TrimPrefix(fields[0], "cpu")); err == nil {
+			t.ID = id
+			cores = append(cores, t)
+		}
+	}
+	return all, cores
+}
+
+// getCoreFrequencies returns the current frequency in MHz of each CPU in
+// times, or nil if cpufreq is not available.
+func getCoreFrequencies(times []CPUTime) []int {
+	var freqs []int
+	found := false
+	for _, t := range times {
+		khz, err := readFileInt(filepath.Join(cpuRoot, "cpu"+strconv.Itoa(t.ID), "cpufreq", "scaling_cur_freq"))
+		if err == nil {
+			found = true
+		}
+		freqs = append(freqs, khz/1000)
+	}
+	if !found {
+		return nil
+	}
+	return freqs
+}
@@ -0,0 +1,56 @@
+package hardware
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCPUTimes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "stat", `cpu  100 10 50 800 40 0 0 0 20 0
+cpu0 60 5 25 400 10 0 0 0 20 0
+cpu2 40 5 25 400 30 0 0 0 0 0
+intr 12345
+ctxt 67890`)
+
+	all, cores := readCPUTimes(filepath.Join(root, "stat"))
+	if all != (CPUTime{ID: -1, Busy: 160, Total: 1000}) {
+		t.Fatalf("all = %+v", all)
+	}
+	want := []CPUTime{{ID: 0, Busy: 90, Total: 500}, {ID: 2, Busy: 70, Total: 500}}
+	if len(cores) != len(want) {
+		t.Fatalf("cores = %+v, want %+v", cores, want)
+	}
+	for i := range want {
+		if cores[i] != want[i] {
+			t.Errorf("core %d = %+v, want %+v", i, cores[i], want[i])
+		}
+	}
+
+	next := CPUTime{ID: -1, Busy: 235, Total: 1100}
+	if got := next.Utilization(all); got != 75 {
+		t.Errorf("Utilization = %v, want 75", got)
+	}
+	if got := all.Utilization(all); got != 0 {
+		t.Errorf("Utilization with no elapsed time = %v, want 0", got)
+	}
+
+	if all, cores := readCPUTimes(filepath.Join(root, "missing")); all.Total != 0 || cores != nil {
+		t.Errorf("missing file = %+v %+v, want zero", all, cores)
+	}
+}
+
+func TestReadPackageEnergy(t *testing.T) {
+	root := t.TempDir()
+	if got := readPackageEnergy(root); got != -1 {
+		t.Fatalf("no RAPL = %d, want -1", got)
+	}
+
+	writeFile(t, root, "intel-rapl:0/energy_uj", "1000")
+	writeFile(t, root, "intel-rapl:0/intel-rapl:0:0/energy_uj", "400")
+	writeFile(t, root, "intel-rapl:0:0/energy_uj", "400")
+	writeFile(t, root, "intel-rapl:1/energy_uj", "2500")
+	if got := readPackageEnergy(root); got != 3500 {
+		t.Fatalf("package energy = %d, want 3500", got)
+	}
+}
@@ -0,0 +1,196 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// thermalRoot is the sysfs directory of thermal zones.
+const thermalRoot = "/sys/class/thermal"
+
+// tempRule says where one platform exposes its CPU temperature: an hwmon
+// chip by name, or a thermal zone by type.
+type tempRule struct {
+	Platform string
+	Chip     string   // hwmon name file, empty for a thermal zone rule
+	Zone     string   // Thermal zone type file, empty for an hwmon rule
+	Labels   []string // Label prefixes of the chip's sensor, in order of preference; nil for its first input
+}
+
+// amdTempLabels are the k10temp and zenpower labels of the package
+// temperature, in order of preference: Tdie is the die temperature, Tctl
+// may carry a fan control offset, and Tccd1 is the first core complex on
+// chips that report neither.
+var amdTempLabels = []string{"Tdie", "Tctl", "Tccd1"}
+
+// tempRules are the known CPU temperature sensors, in order of
+// preference. The first rule with a readable sensor wins.
+var tempRules = []tempRule{
+	{Platform: "Intel", Chip: "coretemp", Labels: []string{"Package id", "Core"}},
+	{Platform: "AMD", Chip: "k10temp", Labels: amdTempLabels},
+	{Platform: "AMD", Chip: "zenpower", Labels: amdTempLabels},
+	{Platform: "Raspberry Pi", Zone: "cpu-thermal"},
+	{Platform: "Raspberry Pi", Chip: "cpu_thermal"},
+	{Platform: "Ampere eMAG", Chip: "apm_xgene", Labels: []string{"SoC Temperature"}},
+	{Platform: "ARM SoC", Zone: "soc_thermal"},
+	{Platform: "ARM SoC", Zone: "soc-thermal"},
+	{Platform: "ARM SoC", Zone: "cpu_thermal"},
+	{Platform: "x86 package", Zone: "x86_pkg_temp"},
+}
+
+// acpiZone is the thermal zone type of the ACPI thermal zone. It is often
+// a board or ambient sensor, so it comes after the labelled sensors of
+// findLabelledTemperature. Ampere Altra and AWS Graviton hosts have no
+// rule and fall back to it: the Altra SoC sensors are read by the smpro
+// driver on the BMC, not on the host, and Graviton has no host sensor
+// driver.
+const acpiZone = "acpitz"
+
+// chipLabels returns the sensor labels of the tempRules rule for the
+// hwmon chip named name. Returns false if no rule names the chip.
+func chipLabels(name string) ([]string, bool) {
+	for _, rule := range tempRules {
+		if rule.Chip != "" && rule.Chip == name {
+			return rule.Labels, true
+		}
+	}
+	return nil, false
+}
+
+// getCPUTemperature reads the CPU temperature.
+// Returns temperature in Celsius, or 0 if not available.
+func getCPUTemperature() float64 {
+	_, temp := findCPUTemperature()
+	return temp
+}
+
+// findCPUTemperature returns the path and value in Celsius of the CPU
+// temperature sensor, or an empty path if there is none.
+func findCPUTemperature() (string, float64) {
+	return findCPUTemperatureIn(hwmonRoot, thermalRoot)
+}
+
+// findCPUTemperatureIn finds the CPU temperature sensor below the given
+// hwmon and thermal roots: the first of tempRules that matches, else the
+// first sensor whose label mentions a core, CPU or package, else the ACPI
+// thermal zone.
+func findCPUTemperatureIn(hwmon, thermal string) (string, float64) {
+	chips, _ := filepath.Glob(filepath.Join(hwmon, "hwmon*"))
+	sortNatural(chips)
+	zones, _ := filepath.Glob(filepath.Join(thermal, "thermal_zone*"))
+	sortNatural(zones)
+
+	for _, rule := range tempRules {
+		if rule.Zone != "" {
+			if path, temp, ok := readZoneTemperature(zones, rule.Zone); ok {
+				return path, temp
+			}
+			continue
+		}
+		for _, chip := range chips {
+			if readName(chip, "name") != rule.Chip {
+				continue
+			}
+			if path, temp, ok := readChipTemperature(chip, rule.Labels); ok {
+				return path, temp
+			}
+		}
+	}
+	if path, temp := findLabelledTemperature(zones, chips); path != "" {
+		return path, temp
+	}
+	path, temp, _ := readZoneTemperature(zones, acpiZone)
+	return path, temp
+}
+
+// readZoneTemperature returns the first readable thermal zone of type typ.
+func readZoneTemperature(zones []string, typ string) (string, float64, bool) {
+	for _, zone := range zones {
+		if readName(zone, "type") != typ {
+			continue
+		}
+		if path, temp, ok := readTemperature(filepath.Join(zone, "temp")); ok {
+			return path, temp, true
+		}
+	}
+	return "", 0, false
+}
+
+// findLabelledTemperature returns the first thermal zone, or else hwmon
+// sensor, that is unlabelled or labelled as a core, CPU or package. It
+// is the fallback for platforms without a rule, and skips the ACPI zone.
+func findLabelledTemperature(zones, chips []string) (string, float64) {
+	var paths []string
+	for _, zone := range zones {
+		if readName(zone, "type") != acpiZone {
+			paths = append(paths, filepath.Join(zone, "temp"))
+		}
+	}
+	for _, chip := range chips {
+		inputs, _ := filepath.Glob(filepath.Join(chip, "temp*_input"))
+		sortNatural(inputs)
+		paths = append(paths, inputs...)
+	}
+
+	for _, path := range paths {
+		label := strings.ToLower(readSensorLabel(path))
+		if label != "" &&
+			!strings.Contains(label, "core") &&
+			!strings.Contains(label, "cpu") &&
+			!strings.Contains(label, "package") {
+			continue
+		}
+		if path, temp, ok := readTemperature(path); ok {
+			return path, temp
+		}
+	}
+	return "", 0
+}
+
+// readChipTemperature returns the sensor of the hwmon chip in dir whose
+// label starts with the first of labels it has, or its first input if
+// labels is nil.
+func readChipTemperature(dir string, labels []string) (string, float64, bool) {
+	inputs, _ := filepath.Glob(filepath.Join(dir, "temp*_input"))
+	sortNatural(inputs)
+	if labels == nil {
+		for _, path := range inputs {
+			if path, temp, ok := readTemperature(path); ok {
+				return path, temp, true
+			}
+		}
+		return "", 0, false
+	}
+	for _, prefix := range labels {
+		for _, path := range inputs {
+			if !strings.HasPrefix(readSensorLabel(path), prefix) {
+				continue
+			}
+			if path, temp, ok := readTemperature(path); ok {
+				return path, temp, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// readTemperature reads a millidegree temperature file. Zero and
+// negative readings are rejected: they come from unconnected inputs.
+func readTemperature(path string) (string, float64, bool) {
+	milli, err := readFileInt(path)
+	if err != nil || milli <= 0 {
+		return "", 0, false
+	}
+	return path, float64(milli) / 1000, true
+}
+
+// readName returns the trimmed content of the name or type file of a
+// sysfs device directory, or an empty string.
+func readName(dir, file string) string {
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,164 @@
+package hardware
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindCPUTemperature(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string // Fixture files below a sysfs class root, "hwmon/..." or "thermal/..."
+		path  string            // Expected sensor, relative to the root
+		temp  float64
+	}{
+		{
+			name: "Intel coretemp over ACPI zone",
+			files: map[string]string{
+				"thermal/thermal_zone0/type": "acpitz",
+				"thermal/thermal_zone0/temp": "27800",
+				"hwmon/hwmon3/name":          "coretemp",
+				"hwmon/hwmon3/temp2_label":   "Core 0",
+				"hwmon/hwmon3/temp2_input":   "71000",
+				"hwmon/hwmon3/temp1_label":   "Package id 0",
+				"hwmon/hwmon3/temp1_input":   "73000",
+			},
+			path: "hwmon/hwmon3/temp1_input", temp: 73,
+		},
+		{
+			name: "AMD k10temp Tctl",
+			files: map[string]string{
+				"hwmon/hwmon0/name":        "nvme",
+				"hwmon/hwmon0/temp1_label": "Composite",
+				"hwmon/hwmon0/temp1_input": "41850",
+				"hwmon/hwmon2/name":        "k10temp",
+				"hwmon/hwmon2/temp1_label": "Tctl",
+				"hwmon/hwmon2/temp1_input": "65250",
+				"hwmon/hwmon2/temp3_label": "Tccd1",
+				"hwmon/hwmon2/temp3_input": "61000",
+			},
+			path: "hwmon/hwmon2/temp1_input", temp: 65.25,
+		},
+		{
+			name: "AMD k10temp Tdie over Tctl",
+			files: map[string]string{
+				"hwmon/hwmon1/name":        "k10temp",
+				"hwmon/hwmon1/temp1_label": "Tctl",
+				"hwmon/hwmon1/temp1_input": "77000",
+				"hwmon/hwmon1/temp2_label": "Tdie",
+				"hwmon/hwmon1/temp2_input": "50000",
+			},
+			path: "hwmon/hwmon1/temp2_input", temp: 50,
+		},
+		{
+			name: "AMD k10temp Tccd1 only",
+			files: map[string]string{
+				"hwmon/hwmon1/name":        "k10temp",
+				"hwmon/hwmon1/temp3_label": "Tccd1",
+				"hwmon/hwmon1/temp3_input": "58000",
+			},
+			path: "hwmon/hwmon1/temp3_input", temp: 58,
+		},
+		{
+			name: "AMD zenpower",
+			files: map[string]string{
+				"hwmon/hwmon4/name":        "zenpower",
+				"hwmon/hwmon4/temp1_label": "Tdie",
+				"hwmon/hwmon4/temp1_input": "62000",
+			},
+			path: "hwmon/hwmon4/temp1_input", temp: 62,
+		},
+		{
+			name: "Raspberry Pi",
+			files: map[string]string{
+				"thermal/thermal_zone0/type":   "cpu-thermal",
+				"thermal/thermal_zone0/temp":   "48686",
+				"hwmon/hwmon0/name":            "rpi_volt",
+				"hwmon/hwmon0/in0_lcrit_alarm": "0",
+			},
+			path: "thermal/thermal_zone0/temp", temp: 48.686,
+		},
+		{
+			name: "Ampere eMAG",
+			files: map[string]string{
+				"hwmon/hwmon0/name":        "apm_xgene",
+				"hwmon/hwmon0/temp1_label": "SoC Temperature",
+				"hwmon/hwmon0/temp1_input": "44000",
+			},
+			path: "hwmon/hwmon0/temp1_input", temp: 44,
+		},
+		{
+			name: "ARM SoC thermal zone",
+			files: map[string]string{
+				"thermal/thermal_zone0/type": "gpu_thermal",
+				"thermal/thermal_zone0/temp": "39000",
+				"thermal/thermal_zone1/type": "soc_thermal",
+				"thermal/thermal_zone1/temp": "45000",
+			},
+			path: "thermal/thermal_zone1/temp", temp: 45,
+		},
+		{
+			name: "ACPI zone of a server without a vendor driver",
+			files: map[string]string{
+				"thermal/thermal_zone0/type": "acpitz",
+				"thermal/thermal_zone0/temp": "38000",
+			},
+			path: "thermal/thermal_zone0/temp", temp: 38,
+		},
+		{
+			name: "labelled CPU sensor over ACPI zone",
+			files: map[string]string{
+				"thermal/thermal_zone0/type": "acpitz",
+				"thermal/thermal_zone0/temp": "27800",
+				"hwmon/hwmon0/name":          "somechip",
+				"hwmon/hwmon0/temp1_label":   "CPU Temp",
+				"hwmon/hwmon0/temp1_input":   "55000",
+			},
+			path: "hwmon/hwmon0/temp1_input", temp: 55,
+		},
+		{
+			name: "unknown platform falls back to labels",
+			files: map[string]string{
+				"hwmon/hwmon0/name":        "nvme",
+				"hwmon/hwmon0/temp1_label": "Composite",
+				"hwmon/hwmon0/temp1_input": "41850",
+				"hwmon/hwmon1/name":        "somechip",
+				"hwmon/hwmon1/temp1_label": "CPU Temp",
+				"hwmon/hwmon1/temp1_input": "55000",
+			},
+			path: "hwmon/hwmon1/temp1_input", temp: 55,
+		},
+		{
+			name: "disconnected input is skipped",
+			files: map[string]string{
+				"hwmon/hwmon0/name":        "coretemp",
+				"hwmon/hwmon0/temp1_label": "Package id 0",
+				"hwmon/hwmon0/temp1_input": "0",
+				"hwmon/hwmon0/temp2_label": "Core 0",
+				"hwmon/hwmon0/temp2_input": "60000",
+			},
+			path: "hwmon/hwmon0/temp2_input", temp: 60,
+		},
+		{
+			name:  "no sensors",
+			files: map[string]string{"hwmon/hwmon0/name": "nvme"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			for path, content := range tc.files {
+				writeFile(t, root, path, content)
+			}
+			path, temp := findCPUTemperatureIn(filepath.Join(root, "hwmon"), filepath.Join(root, "thermal"))
+			if got := strings.TrimPrefix(path, root+"/"); got != tc.path {
+				t.Errorf("sensor = %q, want %q", got, tc.path)
+			}
+			if temp != tc.temp {
+				t.Errorf("temperature = %v, want %v", temp, tc.temp)
+			}
+		})
+	}
+}
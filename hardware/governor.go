@@ -0,0 +1,133 @@
+package hardware
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PowerSettings records the cpufreq settings that change burn results:
+// scaling governors, energy/performance preferences and turbo boost.
+// Maps count how many CPUs use each value; they are empty when cpufreq
+// is not available.
+type PowerSettings struct {
+	Governors   map[string]int `json:"governors"`    // scaling_governor → CPUs
+	EPP         map[string]int `json:"epp"`          // energy_performance_preference → CPUs
+	Turbo       string         `json:"turbo"`        // "enabled", "disabled" or "" if unknown
+	TurboSource string         `json:"turbo_source"` // sysfs file Turbo was read from
+}
+
+// GetPowerSettings reads the governor and energy/performance preference
+// of every CPU, and the global turbo boost state.
+func GetPowerSettings() PowerSettings {
+	return readPowerSettings(cpuRoot)
+}
+
+// readPowerSettings reads power settings from the CPU sysfs tree at root.
+func readPowerSettings(root string) PowerSettings {
+	ps := PowerSettings{
+		Governors: make(map[string]int),
+		EPP:       make(map[string]int),
+	}
+
+	cpus, _ := filepath.Glob(filepath.Join(root, "cpu[0-9]*", "cpufreq"))
+	for _, dir := range cpus {
+		if gov := readFileString(filepath.Join(dir, "scaling_governor")); gov != "" {
+			ps.Governors[gov]++
+		}
+		if epp := readFileString(filepath.Join(dir, "energy_performance_preference")); epp != "" {
+			ps.EPP[epp]++
+		}
+	}
+
+	// intel_pstate reports the inverse ("no_turbo"), acpi-cpufreq and
+	// amd-pstate report "boost", globally or per policy
+	turboFiles := []struct {
+		path     string
+		disabled string
+	}{
+		{filepath.Join(root, "intel_pstate", "no_turbo"), "1"},
+		{filepath.Join(root, "cpufreq", "boost"), "0"},
+		{filepath.Join(root, "cpufreq", "policy0", "boost"), "0"},
+	}
+	for _, tf := range turboFiles {
+		value := readFileString(tf.path)
+		if value == "" {
+			continue
+		}
+		ps.Turbo = "enabled"
+		if value == tf.disabled {
+			ps.Turbo = "disabled"
+		}
+		ps.TurboSource = tf.path
+		break
+	}
+
+	return ps
+}
+
+// Warnings describes every setting that keeps the CPUs from running at
+// their best sustained performance, so results may not be comparable.
+func (ps PowerSettings) Warnings() []string {
+	var warnings []string
+	for _, gov := range sortedKeys(ps.Governors) {
+		if gov != "performance" {
+			warnings = append(warnings, fmt.Sprintf("%s on the %q cpufreq governor, not \"performance\"",
+				pluralCPUs(ps.Governors[gov]), gov))
+		}
+	}
+	for _, epp := range sortedKeys(ps.EPP) {
+		if epp != "performance" {
+			warnings = append(warnings, fmt.Sprintf("%s with energy_performance_preference %q, not \"performance\"",
+				pluralCPUs(ps.EPP[epp]), epp))
+		}
+	}
+	if ps.Turbo == "disabled" {
+		warnings = append(warnings, fmt.Sprintf("turbo boost is disabled (%s)", ps.TurboSource))
+	}
+	return warnings
+}
+
+// String summarizes the settings in one line, such as
+// "governor performance×8, epp balance_performance×8, turbo enabled".
+func (ps PowerSettings) String() string {
+	parts := []string{}
+	if len(ps.Governors) > 0 {
+		parts = append(parts, "governor "+formatCounts(ps.Governors))
+	}
+	if len(ps.EPP) > 0 {
+		parts = append(parts, "epp "+formatCounts(ps.EPP))
+	}
+	if ps.Turbo != "" {
+		parts = append(parts, "turbo "+ps.Turbo)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatCounts renders a value → count map as "a×2 b×6".
+func formatCounts(counts map[string]int) string {
+	parts := []string{}
+	for _, k := range sortedKeys(counts) {
+		parts = append(parts, fmt.Sprintf("%s×%d", k, counts[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// pluralCPUs returns "1 CPU" or "n CPUs".
+func pluralCPUs(n int) string {
+	if n == 1 {
+		return "1 CPU"
+	}
+	return fmt.Sprintf("%d CPUs", n)
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
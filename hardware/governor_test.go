@@ -0,0 +1,70 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFile creates path below root with the given content.
+func writeFile(t *testing.T, root, path, content string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadPowerSettings(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "cpu0/cpufreq/scaling_governor", "performance")
+	writeFile(t, root, "cpu1/cpufreq/scaling_governor", "powersave")
+	writeFile(t, root, "cpu2/cpufreq/scaling_governor", "powersave")
+	writeFile(t, root, "cpu0/cpufreq/energy_performance_preference", "performance")
+	writeFile(t, root, "cpu1/cpufreq/energy_performance_preference", "balance_power")
+	writeFile(t, root, "intel_pstate/no_turbo", "1")
+
+	ps := readPowerSettings(root)
+	if want := map[string]int{"performance": 1, "powersave": 2}; !reflect.DeepEqual(ps.Governors, want) {
+		t.Errorf("governors = %v, want %v", ps.Governors, want)
+	}
+	if ps.Turbo != "disabled" {
+		t.Errorf("turbo = %q, want disabled", ps.Turbo)
+	}
+
+	want := []string{
+		`2 CPUs on the "powersave" cpufreq governor, not "performance"`,
+		`1 CPU with energy_performance_preference "balance_power", not "performance"`,
+		"turbo boost is disabled (" + filepath.Join(root, "intel_pstate/no_turbo") + ")",
+	}
+	if got := ps.Warnings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings =\n%q\nwant\n%q", got, want)
+	}
+	if got := ps.String(); got != "governor performance×1 powersave×2, epp balance_power×1 performance×1, turbo disabled" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestReadPowerSettingsBoost(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "cpu0/cpufreq/scaling_governor", "performance")
+	writeFile(t, root, "cpufreq/boost", "1")
+
+	ps := readPowerSettings(root)
+	if ps.Turbo != "enabled" {
+		t.Errorf("turbo = %q, want enabled", ps.Turbo)
+	}
+	if w := ps.Warnings(); len(w) != 0 {
+		t.Errorf("unexpected warnings: %q", w)
+	}
+
+	// No cpufreq at all gives no settings and no warnings
+	empty := readPowerSettings(t.TempDir())
+	if empty.String() != "" || len(empty.Warnings()) != 0 {
+		t.Errorf("empty settings = %+v", empty)
+	}
+}
@@ -0,0 +1,109 @@
+package hardware
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Inventory identifies the machine a burn ran on.
+// Fields that could not be read are left empty or zero.
+type Inventory struct {
+	Hostname  string `json:"hostname"`
+	Kernel    string `json:"kernel"`         // Kernel release, as in uname -r
+	Vendor    string `json:"vendor"`         // CPU vendor, e.g. GenuineIntel or AuthenticAMD
+	CPUModel  string `json:"cpu_model"`      // CPU model name from /proc/cpuinfo
+	Microcode string `json:"microcode"`      // Microcode revision of the first CPU
+	Packages  int    `json:"packages"`       // Physical packages (sockets)
+	Cores     int    `json:"cores"`          // Physical cores
+	Threads   int    `json:"threads"`        // Online logical CPUs
+	SMT       string `json:"smt"`            // SMT control: on, off, forceoff or notsupported
+	Governor  string `json:"governor"`       // cpufreq scaling governor of cpu0
+	Driver    string `json:"cpufreq_driver"` // cpufreq scaling driver of cpu0
+}
+
+// GetInventory gathers the CPU model, topology, cpufreq and kernel
+// details of this machine.
+func GetInventory() Inventory {
+	inv := Inventory{
+		Kernel:   readFileString("/proc/sys/kernel/osrelease"),
+		SMT:      readFileString(filepath.Join(cpuRoot, "smt", "control")),
+		Governor: readFileString(filepath.Join(cpuRoot, "cpu0", "cpufreq", "scaling_governor")),
+		Driver:   readFileString(filepath.Join(cpuRoot, "cpu0", "cpufreq", "scaling_driver")),
+	}
+	inv.Hostname, _ = os.Hostname()
+	inv.Vendor, inv.CPUModel, inv.Microcode = readCPUInfo("/proc/cpuinfo")
+
+	topo := GetTopology()
+	inv.Packages = topo.Packages()
+	inv.Cores = topo.Cores()
+	inv.Threads = topo.Threads()
+	return inv
+}
+
+// Short returns a one-line description for headers and screenshots,
+// such as "host · Intel(R) Core(TM) i7-8550U · 4C/8T".
+func (inv Inventory) Short() string {
+	parts := []string{}
+	if inv.Hostname != "" {
+		parts = append(parts, inv.Hostname)
+	}
+	if inv.CPUModel != "" {
+		parts = append(parts, inv.CPUModel)
+	}
+	if inv.Threads > 0 {
+		topo := fmt.Sprintf("%dC/%dT", inv.Cores, inv.Threads)
+		if inv.Packages > 1 {
+			topo = fmt.Sprintf("%dS/", inv.Packages) + topo
+		}
+		parts = append(parts, topo)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// readCPUInfo extracts the vendor, model name and microcode revision of
+// the first processor listed in a /proc/cpuinfo file. ARM kernels do not
+// report "model name", so the board "Model" or "Hardware" is used instead.
+func readCPUInfo(path string) (vendor, model, microcode string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", ""
+	}
+	defer f.Close()
+
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if _, seen := fields[key]; !seen {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+
+	vendor = fields["vendor_id"]
+	if vendor == "" {
+		vendor = fields["CPU implementer"]
+	}
+	for _, key := range []string{"model name", "Model", "Hardware", "Processor"} {
+		if model = fields[key]; model != "" {
+			break
+		}
+	}
+	return vendor, model, fields["microcode"]
+}
+
+// readFileString reads a sysfs or procfs file as a trimmed string.
+// Returns an empty string on error.
+func readFileString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,114 @@
+package hardware
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// nodeRoot is the sysfs directory describing NUMA nodes.
+const nodeRoot = "/sys/devices/system/node"
+
+// Node describes a NUMA node: its CPUs and local memory.
+type Node struct {
+	ID        int   `json:"id"`
+	CPUs      []int `json:"cpus"`      // Logical CPUs of the node
+	MemoryKB  int   `json:"memory_kb"` // Total local memory, 0 if unknown
+	Distances []int `json:"distances"` // Relative access cost to every node, by node order; 10 is local
+}
+
+// GetNodes reads the online NUMA nodes from sysfs, ordered by ID.
+// Returns nil if the kernel does not expose NUMA topology.
+func GetNodes() []Node {
+	return readNodes(nodeRoot)
+}
+
+// readNodes reads the NUMA nodes below root.
+func readNodes(root string) []Node {
+	var ids []int
+	if data, err := os.ReadFile(filepath.Join(root, "online")); err == nil {
+		ids = parseCPUList(string(data))
+	} else {
+		matches, _ := filepath.Glob(filepath.Join(root, "node[0-9]*"))
+		for _, m := range matches {
+			if id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(m), "node")); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		slices.Sort(ids)
+	}
+
+	var nodes []Node
+	for _, id := range ids {
+		dir := filepath.Join(root, "node"+strconv.Itoa(id))
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		node := Node{ID: id, MemoryKB: readNodeMemory(filepath.Join(dir, "meminfo"))}
+		if data, err := os.ReadFile(filepath.Join(dir, "cpulist")); err == nil {
+			node.CPUs = parseCPUList(string(data))
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "distance")); err == nil {
+			for _, field := range strings.Fields(string(data)) {
+				if d, err := strconv.Atoi(field); err == nil {
+					node.Distances = append(node.Distances, d)
+				}
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// readNodeMemory returns the MemTotal of a node meminfo file in kB,
+// from a line such as "Node 0 MemTotal:       65536000 kB".
+func readNodeMemory(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 4 && fields[2] == "MemTotal:" {
+			kb, _ := strconv.Atoi(fields[3])
+			return kb
+		}
+	}
+	return 0
+}
+
+// OnNodes returns the part of the topology on the given NUMA nodes.
+func (t Topology) OnNodes(nodes []int) Topology {
+	var sub Topology
+	for _, cpu := range t.CPUs {
+		if slices.Contains(nodes, cpu.Node) {
+			sub.CPUs = append(sub.CPUs, cpu)
+		}
+	}
+	return sub
+}
+
+// NodeIDs returns the NUMA nodes that have CPUs, in increasing order.
+func (t Topology) NodeIDs() []int {
+	var ids []int
+	for _, cpu := range t.CPUs {
+		ids = append(ids, cpu.Node)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// PackageOf returns the physical package of every logical CPU.
+func (t Topology) PackageOf() map[int]int {
+	packages := make(map[int]int, len(t.CPUs))
+	for _, cpu := range t.CPUs {
+		packages[cpu.ID] = cpu.Package
+	}
+	return packages
+}
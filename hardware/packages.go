@@ -0,0 +1,198 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PackageStats are the readings of one physical package (socket).
+type PackageStats struct {
+	ID          int     `json:"id"`               // physical_package_id
+	CPUFreqCur  int     `json:"cpu_freq_mhz"`     // Mean current MHz of the package's CPUs, 0 without cpufreq
+	CPUFreqMax  int     `json:"cpu_freq_max_mhz"` // Max MHz of the package's first CPU
+	CPUFreqPct  float64 `json:"cpu_freq_pct"`     // CPUFreqCur in percent of CPUFreqMax
+	Temperature float64 `json:"temperature_c"`    // Package temperature in Celsius, 0 without a sensor
+	EnergyUJ    int64   `json:"energy_uj"`        // Cumulative RAPL energy of the package in µJ (-1 if unavailable)
+}
+
+// hwmonRoot is the sysfs directory of hardware monitoring chips.
+const hwmonRoot = "/sys/class/hwmon"
+
+// getPackageStats splits frequency, temperature and energy readings by
+// the physical packages of packageOf. times and freqs are the per-CPU
+// readings of Get. Returns nil with fewer than two packages.
+func getPackageStats(packageOf map[int]int, times []CPUTime, freqs []int) []PackageStats {
+	packages := groupPackages(cpuRoot, packageOf, times, freqs)
+	if packages == nil {
+		return nil
+	}
+	temps := readPackageTemperatures(hwmonRoot, packageOf)
+	energies := readPackageEnergies(powercapRoot)
+	for i := range packages {
+		p := &packages[i]
+		p.Temperature = temps[p.ID]
+		p.EnergyUJ = -1
+		if uj, ok := energies[p.ID]; ok {
+			p.EnergyUJ = uj
+		}
+	}
+	return packages
+}
+
+// groupPackages returns a PackageStats per package of packageOf, with
+// the mean frequency of its CPUs among times and freqs and the max
+// frequency read below root. Returns nil with fewer than two packages.
+func groupPackages(root string, packageOf map[int]int, times []CPUTime, freqs []int) []PackageStats {
+	var ids []int
+	for _, pkg := range packageOf {
+		ids = append(ids, pkg)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) < 2 {
+		return nil
+	}
+
+	packages := make([]PackageStats, len(ids))
+	sums := make([]int, len(ids))
+	counts := make([]int, len(ids))
+	for i, t := range times {
+		pkg, ok := packageOf[t.ID]
+		if !ok || i >= len(freqs) || freqs[i] == 0 {
+			continue
+		}
+		j, _ := slices.BinarySearch(ids, pkg)
+		sums[j] += freqs[i]
+		counts[j]++
+	}
+	for j, pkg := range ids {
+		p := &packages[j]
+		p.ID = pkg
+		if counts[j] == 0 {
+			continue
+		}
+		p.CPUFreqCur = sums[j] / counts[j]
+		if khz, err := readFileInt(filepath.Join(root, "cpu"+strconv.Itoa(firstCPU(packageOf, pkg)), "cpufreq", "scaling_max_freq")); err == nil {
+			p.CPUFreqMax = khz / 1000
+		}
+		if p.CPUFreqMax > 0 {
+			p.CPUFreqPct = float64(p.CPUFreqCur) / float64(p.CPUFreqMax) * 100
+		}
+	}
+	return packages
+}
+
+// firstCPU returns the lowest logical CPU of package pkg.
+func firstCPU(packageOf map[int]int, pkg int) int {
+	first := -1
+	for cpu, p := range packageOf {
+		if p == pkg && (first < 0 || cpu < first) {
+			first = cpu
+		}
+	}
+	return first
+}
+
+// readPackageTemperatures reads the temperature of every physical package
+// from the hwmon chips below root, by package ID:
+//
+//   - coretemp (Intel) has one chip per package, with a "Package id N"
+//     sensor naming the package.
+//   - Other chips of tempRules, such as k10temp (AMD), have one chip per
+//     package, read by the rule's labels; the chip's PCI device lists the
+//     CPUs near it in local_cpulist. Chips without it are matched to the
+//     remaining packages in hwmon order.
+func readPackageTemperatures(root string, packageOf map[int]int) map[int]float64 {
+	temps := make(map[int]float64)
+	var unmatched []float64
+
+	dirs, _ := filepath.Glob(filepath.Join(root, "hwmon*"))
+	sortNatural(dirs)
+	for _, dir := range dirs {
+		name := readName(dir, "name")
+		switch name {
+		case "coretemp":
+			inputs, _ := filepath.Glob(filepath.Join(dir, "temp*_input"))
+			for _, path := range inputs {
+				id, ok := strings.CutPrefix(readSensorLabel(path), "Package id ")
+				if !ok {
+					continue
+				}
+				pkg, err := strconv.Atoi(id)
+				if err != nil {
+					continue
+				}
+				if milli, err := readFileInt(path); err == nil {
+					temps[pkg] = float64(milli) / 1000
+				}
+			}
+
+		default:
+			labels, ok := chipLabels(name)
+			if !ok {
+				continue
+			}
+			_, temp, ok := readChipTemperature(dir, labels)
+			if !ok {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "device", "local_cpulist"))
+			cpus := parseCPUList(string(data))
+			if err != nil || len(cpus) == 0 {
+				unmatched = append(unmatched, temp)
+				continue
+			}
+			if pkg, ok := packageOf[cpus[0]]; ok {
+				temps[pkg] = temp
+			}
+		}
+	}
+
+	// Chips without a CPU list come in socket order, like their PCI devices
+	if len(unmatched) > 0 {
+		var ids []int
+		for _, pkg := range packageOf {
+			if _, ok := temps[pkg]; !ok {
+				ids = append(ids, pkg)
+			}
+		}
+		slices.Sort(ids)
+		ids = slices.Compact(ids)
+		for i := 0; i < len(ids) && i < len(unmatched); i++ {
+			temps[ids[i]] = unmatched[i]
+		}
+	}
+	return temps
+}
+
+// readPackageEnergies reads the energy_uj of every RAPL package domain
+// below root, by package ID. Domains are named "package-N" for package N.
+func readPackageEnergies(root string) map[int]int64 {
+	energies := make(map[int]int64)
+	matches, _ := filepath.Glob(filepath.Join(root, "intel-rapl:*", "energy_uj"))
+	for _, path := range matches {
+		dir := filepath.Dir(path)
+		if strings.Count(filepath.Base(dir), ":") != 1 {
+			continue
+		}
+		id, ok := strings.CutPrefix(readName(dir, "name"), "package-")
+		if !ok {
+			continue
+		}
+		pkg, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if uj, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
+			energies[pkg] = uj
+		}
+	}
+	return energies
+}
@@ -0,0 +1,80 @@
+package hardware
+
+import (
+	"reflect"
+	"testing"
+)
+
+// twoSockets maps CPUs 0-1 to package 0 and 2-3 to package 1.
+var twoSockets = map[int]int{0: 0, 1: 0, 2: 1, 3: 1}
+
+func TestReadPackageTemperatures(t *testing.T) {
+	// Intel: one coretemp chip per package, named by the package sensor
+	root := t.TempDir()
+	writeFile(t, root, "hwmon0/name", "acpitz")
+	writeFile(t, root, "hwmon0/temp1_input", "99000")
+	for _, chip := range []struct{ dir, id, milli string }{{"hwmon1", "0", "61000"}, {"hwmon2", "1", "67500"}} {
+		writeFile(t, root, chip.dir+"/name", "coretemp")
+		writeFile(t, root, chip.dir+"/temp1_label", "Package id "+chip.id)
+		writeFile(t, root, chip.dir+"/temp1_input", chip.milli)
+		writeFile(t, root, chip.dir+"/temp2_label", "Core 0")
+		writeFile(t, root, chip.dir+"/temp2_input", "90000")
+	}
+	want := map[int]float64{0: 61, 1: 67.5}
+	if got := readPackageTemperatures(root, twoSockets); !reflect.DeepEqual(got, want) {
+		t.Errorf("coretemp = %v, want %v", got, want)
+	}
+
+	// AMD: k10temp chips, matched by local_cpulist or else in hwmon order;
+	// Tdie is preferred over the offset Tctl
+	root = t.TempDir()
+	writeFile(t, root, "hwmon3/name", "k10temp")
+	writeFile(t, root, "hwmon3/device/local_cpulist", "2-3")
+	writeFile(t, root, "hwmon3/temp1_label", "Tctl")
+	writeFile(t, root, "hwmon3/temp1_input", "80000")
+	writeFile(t, root, "hwmon3/temp2_label", "Tdie")
+	writeFile(t, root, "hwmon3/temp2_input", "70000")
+	writeFile(t, root, "hwmon10/name", "zenpower")
+	writeFile(t, root, "hwmon10/temp1_label", "Tctl")
+	writeFile(t, root, "hwmon10/temp1_input", "55000")
+	want = map[int]float64{0: 55, 1: 70}
+	if got := readPackageTemperatures(root, twoSockets); !reflect.DeepEqual(got, want) {
+		t.Errorf("k10temp = %v, want %v", got, want)
+	}
+}
+
+func TestReadPackageEnergies(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "intel-rapl:0/name", "package-0")
+	writeFile(t, root, "intel-rapl:0/energy_uj", "1000")
+	writeFile(t, root, "intel-rapl:0:0/name", "core")
+	writeFile(t, root, "intel-rapl:0:0/energy_uj", "400")
+	writeFile(t, root, "intel-rapl:1/name", "package-1")
+	writeFile(t, root, "intel-rapl:1/energy_uj", "2500")
+	writeFile(t, root, "intel-rapl:2/name", "psys")
+	writeFile(t, root, "intel-rapl:2/energy_uj", "9000")
+
+	want := map[int]int64{0: 1000, 1: 2500}
+	if got := readPackageEnergies(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("readPackageEnergies = %v, want %v", got, want)
+	}
+}
+
+func TestGroupPackages(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "cpu0/cpufreq/scaling_max_freq", "4000000")
+	writeFile(t, root, "cpu2/cpufreq/scaling_max_freq", "3000000")
+	times := []CPUTime{{ID: 0}, {ID: 1}, {ID: 2}, {ID: 3}}
+
+	got := groupPackages(root, twoSockets, times, []int{3000, 4000, 1500, 0})
+	want := []PackageStats{
+		{ID: 0, CPUFreqCur: 3500, CPUFreqMax: 4000, CPUFreqPct: 87.5},
+		{ID: 1, CPUFreqCur: 1500, CPUFreqMax: 3000, CPUFreqPct: 50},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupPackages = %+v, want %+v", got, want)
+	}
+	if got := groupPackages(root, map[int]int{0: 0, 1: 0}, times, nil); got != nil {
+		t.Errorf("groupPackages of one package = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,95 @@
+package hardware
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Placement is a strategy for choosing the logical CPUs that pinned
+// workers run on.
+type Placement string
+
+// Placement strategies. Each orders the logical CPUs; worker i is pinned
+// to the i-th CPU of the order.
+const (
+	// PlaceSpread uses one thread of every physical core before any
+	// core's SMT siblings, so workers do not share cores until they must.
+	PlaceSpread Placement = "spread"
+	// PlacePack fills both SMT siblings of a core before the next core.
+	PlacePack Placement = "pack"
+	// PlaceNode fills one NUMA node before the next, spreading over the
+	// node's cores.
+	PlaceNode Placement = "node"
+	// PlaceSocket fills one physical package before the next, spreading
+	// over the package's cores.
+	PlaceSocket Placement = "socket"
+)
+
+// Placements lists every placement strategy.
+var Placements = []Placement{PlaceSpread, PlacePack, PlaceNode, PlaceSocket}
+
+// ParsePlacement returns the placement strategy with the given name.
+func ParsePlacement(name string) (Placement, bool) {
+	p := Placement(name)
+	return p, slices.Contains(Placements, p)
+}
+
+// Place returns the logical CPUs of the topology in the order the
+// placement strategy uses them. Returns nil for an unknown strategy.
+func (t Topology) Place(p Placement) []int {
+	// rank is the position of a CPU among its core's SMT siblings
+	type key struct{ node, pkg, core, rank, id int }
+	keys := make([]key, len(t.CPUs))
+	for i, cpu := range t.CPUs {
+		keys[i] = key{cpu.Node, cpu.Package, cpu.Core, max(slices.Index(cpu.Siblings, cpu.ID), 0), cpu.ID}
+	}
+
+	var order func(k key) []int
+	switch p {
+	case PlaceSpread:
+		order = func(k key) []int { return []int{k.rank, k.pkg, k.core} }
+	case PlacePack:
+		order = func(k key) []int { return []int{k.pkg, k.core, k.rank} }
+	case PlaceNode:
+		order = func(k key) []int { return []int{k.node, k.rank, k.pkg, k.core} }
+	case PlaceSocket:
+		order = func(k key) []int { return []int{k.pkg, k.rank, k.core} }
+	default:
+		return nil
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return slices.Compare(append(order(keys[i]), keys[i].id), append(order(keys[j]), keys[j].id)) < 0
+	})
+
+	cpus := make([]int, len(keys))
+	for i, k := range keys {
+		cpus[i] = k.id
+	}
+	return cpus
+}
+
+// FormatCPUList formats CPU numbers as a kernel CPU list such as
+// "0-3,8,10-11", the inverse of the lists read from sysfs. Duplicates are
+// listed once.
+func FormatCPUList(cpus []int) string {
+	sorted := slices.Clone(cpus)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		part := strconv.Itoa(sorted[i])
+		if j > i {
+			part += "-" + strconv.Itoa(sorted[j])
+		}
+		parts = append(parts, part)
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,42 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// powercapRoot is where the kernel exposes RAPL energy counters.
+const powercapRoot = "/sys/class/powercap"
+
+// getPackageEnergy returns the cumulative energy of every CPU package in
+// microjoules, or -1 if no RAPL counter is readable.
+func getPackageEnergy() int64 {
+	return readPackageEnergy(powercapRoot)
+}
+
+// readPackageEnergy sums energy_uj over the RAPL package domains under
+// root. Package domains are "intel-rapl:<n>"; their subzones, such as
+// "intel-rapl:0:0" for the cores, are part of the package and skipped.
+// AMD CPUs use the same intel-rapl names. energy_uj is often only
+// readable by root.
+func readPackageEnergy(root string) int64 {
+	matches, _ := filepath.Glob(filepath.Join(root, "intel-rapl:*", "energy_uj"))
+	var total int64 = -1
+	for _, path := range matches {
+		if strings.Count(filepath.Base(filepath.Dir(path)), ":") != 1 {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		uj, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			continue
+		}
+		total = max(total, 0) + uj
+	}
+	return total
+}
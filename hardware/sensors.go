@@ -0,0 +1,188 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Sensor is a single raw reading found in sysfs.
+type Sensor struct {
+	Kind  string  `json:"kind"`  // "freq", "temp", "fan", "power" or "cgroup"
+	Name  string  `json:"name"`  // hwmon chip, thermal zone type or CPU
+	Label string  `json:"label"` // Sensor label, if any
+	Path  string  `json:"path"`  // sysfs file the value was read from
+	Value float64 `json:"value"` // Value converted to Unit
+	Unit  string  `json:"unit"`
+	Used  bool    `json:"used"` // Whether Get reports this sensor
+}
+
+// ListSensors reads every sensor goburn knows how to find, whether or not
+// Get uses it. Unreadable files are skipped.
+func ListSensors() []Sensor {
+	var sensors []Sensor
+	sensors = append(sensors, listFrequencySensors()...)
+	sensors = append(sensors, listTemperatureSensors()...)
+	sensors = append(sensors, listFanSensors()...)
+	sensors = append(sensors, listPowerSensors()...)
+	sensors = append(sensors, listCgroupSensors()...)
+	return sensors
+}
+
+// listFrequencySensors returns the current frequency of every CPU.
+func listFrequencySensors() []Sensor {
+	var sensors []Sensor
+	matches, _ := filepath.Glob(filepath.Join(cpuRoot, "cpu[0-9]*", "cpufreq", "scaling_cur_freq"))
+	sortNatural(matches)
+	for _, path := range matches {
+		khz, err := readFileInt(path)
+		if err != nil {
+			continue
+		}
+		cpu := filepath.Base(filepath.Dir(filepath.Dir(path)))
+		sensors = append(sensors, Sensor{
+			Kind:  "freq",
+			Name:  cpu,
+			Path:  path,
+			Value: float64(khz) / 1000,
+			Unit:  "MHz",
+			Used:  cpu == "cpu0",
+		})
+	}
+	return sensors
+}
+
+// listTemperatureSensors returns every thermal zone and hwmon temperature.
+func listTemperatureSensors() []Sensor {
+	used, _ := findCPUTemperature()
+
+	var sensors []Sensor
+	for _, pattern := range temperaturePatterns {
+		matches, _ := filepath.Glob(pattern)
+		sortNatural(matches)
+		for _, path := range matches {
+			milli, err := readFileInt(path)
+			if err != nil {
+				continue
+			}
+			sensors = append(sensors, Sensor{
+				Kind:  "temp",
+				Name:  sensorDeviceName(path),
+				Label: readSensorLabel(path),
+				Path:  path,
+				Value: float64(milli) / 1000,
+				Unit:  "°C",
+				Used:  path == used,
+			})
+		}
+	}
+	return sensors
+}
+
+// listFanSensors returns every hwmon fan, including stopped ones.
+func listFanSensors() []Sensor {
+	var sensors []Sensor
+	matches, _ := filepath.Glob("/sys/class/hwmon/hwmon*/fan*_input")
+	sortNatural(matches)
+	for _, path := range matches {
+		rpm, err := readFileInt(path)
+		if err != nil {
+			continue
+		}
+		sensors = append(sensors, Sensor{
+			Kind:  "fan",
+			Name:  sensorDeviceName(path),
+			Label: readSensorLabel(path),
+			Path:  path,
+			Value: float64(rpm),
+			Unit:  "RPM",
+			Used:  rpm > 0,
+		})
+	}
+	return sensors
+}
+
+// listPowerSensors returns every readable RAPL package energy counter.
+func listPowerSensors() []Sensor {
+	var sensors []Sensor
+	matches, _ := filepath.Glob(filepath.Join(powercapRoot, "intel-rapl:*", "energy_uj"))
+	sortNatural(matches)
+	for _, path := range matches {
+		uj, err := readFileInt(path)
+		if err != nil {
+			continue
+		}
+		dir := filepath.Base(filepath.Dir(path))
+		label, _ := os.ReadFile(filepath.Join(filepath.Dir(path), "name"))
+		sensors = append(sensors, Sensor{
+			Kind:  "power",
+			Name:  dir,
+			Label: strings.TrimSpace(string(label)),
+			Path:  path,
+			Value: float64(uj) / 1_000_000,
+			Unit:  "J",
+			Used:  strings.Count(dir, ":") == 1,
+		})
+	}
+	return sensors
+}
+
+// listCgroupSensors returns the cgroup v2 CPU throttling counter.
+func listCgroupSensors() []Sensor {
+	path, ok := getCgroupPath()
+	if !ok {
+		return nil
+	}
+	usec := getCgroupThrottled()
+	if usec < 0 {
+		return nil
+	}
+	return []Sensor{{
+		Kind:  "cgroup",
+		Name:  path,
+		Label: "throttled_usec",
+		Path:  filepath.Join(cgroupRoot, path, "cpu.stat"),
+		Value: float64(usec) / 1000,
+		Unit:  "ms",
+		Used:  true,
+	}}
+}
+
+// sensorDeviceName returns the hwmon chip name or thermal zone type
+// of the device directory containing path.
+func sensorDeviceName(path string) string {
+	dir := filepath.Dir(path)
+	for _, file := range []string{"name", "type"} {
+		if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return filepath.Base(dir)
+}
+
+// sortNatural sorts paths so that "cpu2" comes before "cpu10".
+func sortNatural(paths []string) {
+	key := func(s string) string {
+		var b strings.Builder
+		digits := ""
+		flush := func() {
+			if digits != "" {
+				b.WriteString(strings.Repeat("0", 8-min(len(digits), 8)))
+				b.WriteString(digits)
+				digits = ""
+			}
+		}
+		for _, r := range s {
+			if r >= '0' && r <= '9' {
+				digits += string(r)
+				continue
+			}
+			flush()
+			b.WriteRune(r)
+		}
+		flush()
+		return b.String()
+	}
+	sort.Slice(paths, func(i, j int) bool { return key(paths[i]) < key(paths[j]) })
+}
@@ -1,5 +1,7 @@
 // Package hardware provides system hardware monitoring capabilities.
-// It reads CPU frequency, temperature, and fan speeds from Linux sysfs.
+// It reads CPU frequency, temperature, fan speeds and package energy from
+// Linux sysfs, CPU time from procfs, and CPU limits and throttling from
+// cgroup v2.
 package hardware
 
 import (
@@ -11,19 +13,52 @@ import (
 
 // Stats represents current hardware metrics.
 type Stats struct {
-	CPUFreqPct  float64 // CPU frequency percentage (current/max * 100)
-	CPUFreqCur  int     // Current frequency in MHz
-	CPUFreqMax  int     // Max frequency in MHz
-	Temperature float64 // CPU temperature in Celsius
-	FanRPMs     []int   // Fan speeds in RPM
+	CPUFreqPct  float64 `json:"cpu_freq_pct"`     // CPU frequency percentage (current/max * 100)
+	CPUFreqCur  int     `json:"cpu_freq_mhz"`     // Current frequency in MHz
+	CPUFreqMax  int     `json:"cpu_freq_max_mhz"` // Max frequency in MHz
+	Temperature float64 `json:"temperature_c"`    // CPU temperature in Celsius, the hottest package's if several
+	FanRPMs     []int   `json:"fan_rpm"`          // Fan speeds in RPM
+
+	// Packages splits the readings by physical package (socket), ordered
+	// by package ID. Nil on machines with a single package.
+	Packages []PackageStats `json:"packages,omitempty"`
+
+	ThrottledUSec int64 `json:"throttled_usec"` // Cumulative cgroup CPU throttled time in µs (-1 if unavailable)
+	EnergyUJ      int64 `json:"energy_uj"`      // Cumulative RAPL package energy in µJ (-1 if unavailable)
+
+	// Per-CPU readings are only kept in memory, they would dwarf the
+	// rest of a report on large machines
+	CPUTime   CPUTime   `json:"-"` // Cumulative time of all CPUs
+	CoreTimes []CPUTime `json:"-"` // Cumulative time of each online CPU
+	CoreFreqs []int     `json:"-"` // Current MHz of each CPU in CoreTimes, nil without cpufreq
 }
 
-// Get retrieves current hardware statistics from the system.
+// Get retrieves current hardware statistics from the system. On machines
+// with more than one physical package, Packages has the readings of each
+// and Temperature is that of the hottest package, so that a threshold
+// applies to every socket. Get reads the CPU topology on every call;
+// callers sampling repeatedly use GetForPackages instead.
 func Get() Stats {
+	return GetForPackages(GetTopology().PackageOf())
+}
+
+// GetForPackages is Get with the physical package of every logical CPU,
+// as returned by Topology.PackageOf, read once by the caller.
+func GetForPackages(packageOf map[int]int) Stats {
 	stats := Stats{}
 	stats.CPUFreqCur, stats.CPUFreqMax, stats.CPUFreqPct = getCPUFrequency()
 	stats.Temperature = getCPUTemperature()
 	stats.FanRPMs = getFanSpeeds()
+	stats.ThrottledUSec = getCgroupThrottled()
+	stats.EnergyUJ = getPackageEnergy()
+	stats.CPUTime, stats.CoreTimes = getCPUTimes()
+	stats.CoreFreqs = getCoreFrequencies(stats.CoreTimes)
+	stats.Packages = getPackageStats(packageOf, stats.CoreTimes, stats.CoreFreqs)
+	// The first sensor found is on any one socket; report the hottest
+	// package, which is the one thresholds care about
+	for _, p := range stats.Packages {
+		stats.Temperature = max(stats.Temperature, p.Temperature)
+	}
 	return stats
 }
 
@@ -61,46 +96,25 @@ func getCPUFrequency() (cur int, max int, pct float64) {
 	return
 }
 
-// getCPUTemperature reads CPU temperature from thermal zones.
-// Returns temperature in Celsius, or 0 if not available.
-func getCPUTemperature() float64 {
-	// Try common thermal zone patterns
-	patterns := []string{
-		"/sys/class/thermal/thermal_zone*/temp",
-		"/sys/class/hwmon/hwmon*/temp*_input",
-	}
-
-	for _, pattern := range patterns {
-		matches, _ := filepath.Glob(pattern)
-		for _, path := range matches {
-			// Try to read label to identify CPU sensors
-			label := ""
-			labelPath := filepath.Join(
-				filepath.Dir(path),
-				strings.TrimSuffix(filepath.Base(path), "_input")+"_label",
-			)
-			if data, err := os.ReadFile(labelPath); err == nil {
-				label = strings.ToLower(strings.TrimSpace(string(data)))
-			}
-
-			// Filter for CPU-related sensors
-			if label != "" &&
-				!strings.Contains(label, "core") &&
-				!strings.Contains(label, "cpu") &&
-				!strings.Contains(label, "package") {
-				continue
-			}
-
-			temp, err := readFileInt(path)
-			if err != nil {
-				continue
-			}
+// temperaturePatterns are the sysfs temperature files that the sensors
+// command lists.
+var temperaturePatterns = []string{
+	"/sys/class/thermal/thermal_zone*/temp",
+	"/sys/class/hwmon/hwmon*/temp*_input",
+}
 
-			// Temperature is reported in millidegrees
-			return float64(temp) / 1000.0
-		}
+// readSensorLabel returns the label of a hwmon input file,
+// or an empty string if it has none.
+func readSensorLabel(path string) string {
+	labelPath := filepath.Join(
+		filepath.Dir(path),
+		strings.TrimSuffix(filepath.Base(path), "_input")+"_label",
+	)
+	data, err := os.ReadFile(labelPath)
+	if err != nil {
+		return ""
 	}
-	return 0
+	return strings.TrimSpace(string(data))
 }
 
 // getFanSpeeds reads all fan speeds from hwmon sysfs entries.
@@ -0,0 +1,102 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cpuRoot is the sysfs directory describing logical CPUs.
+const cpuRoot = "/sys/devices/system/cpu"
+
+// CPU describes where a logical CPU sits in the machine topology.
+type CPU struct {
+	ID       int   `json:"id"`       // Logical CPU number
+	Package  int   `json:"package"`  // physical_package_id (socket)
+	Core     int   `json:"core"`     // core_id, unique within a package
+	Node     int   `json:"node"`     // NUMA node, 0 if unknown
+	Siblings []int `json:"siblings"` // Logical CPUs sharing this core, including itself
+}
+
+// Topology lists the online logical CPUs of the machine.
+type Topology struct {
+	CPUs []CPU `json:"cpus"`
+}
+
+// GetTopology reads the CPU topology from sysfs.
+// Returns an empty topology if sysfs is not available.
+func GetTopology() Topology {
+	return readTopology(cpuRoot)
+}
+
+// readTopology reads the topology of the online CPUs below root.
+func readTopology(root string) Topology {
+	var ids []int
+	if data, err := os.ReadFile(filepath.Join(root, "online")); err == nil {
+		ids = parseCPUList(string(data))
+	} else {
+		matches, _ := filepath.Glob(filepath.Join(root, "cpu[0-9]*"))
+		for _, m := range matches {
+			if id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(m), "cpu")); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		sort.Ints(ids)
+	}
+
+	var topo Topology
+	for _, id := range ids {
+		dir := filepath.Join(root, "cpu"+strconv.Itoa(id))
+		cpu := CPU{ID: id, Siblings: []int{id}}
+		if v, err := readFileInt(filepath.Join(dir, "topology", "physical_package_id")); err == nil {
+			cpu.Package = v
+		}
+		if v, err := readFileInt(filepath.Join(dir, "topology", "core_id")); err == nil {
+			cpu.Core = v
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "topology", "thread_siblings_list")); err == nil {
+			if siblings := parseCPUList(string(data)); len(siblings) > 0 {
+				cpu.Siblings = siblings
+			}
+		}
+		if nodes, _ := filepath.Glob(filepath.Join(dir, "node[0-9]*")); len(nodes) > 0 {
+			cpu.Node, _ = strconv.Atoi(strings.TrimPrefix(filepath.Base(nodes[0]), "node"))
+		}
+		topo.CPUs = append(topo.CPUs, cpu)
+	}
+	return topo
+}
+
+// Threads returns the number of online logical CPUs.
+func (t Topology) Threads() int {
+	return len(t.CPUs)
+}
+
+// Cores returns the number of distinct physical cores.
+func (t Topology) Cores() int {
+	cores := make(map[[2]int]bool)
+	for _, cpu := range t.CPUs {
+		cores[[2]int{cpu.Package, cpu.Core}] = true
+	}
+	return len(cores)
+}
+
+// Packages returns the number of distinct physical packages (sockets).
+func (t Topology) Packages() int {
+	packages := make(map[int]bool)
+	for _, cpu := range t.CPUs {
+		packages[cpu.Package] = true
+	}
+	return len(packages)
+}
+
+// Nodes returns the number of distinct NUMA nodes.
+func (t Topology) Nodes() int {
+	nodes := make(map[int]bool)
+	for _, cpu := range t.CPUs {
+		nodes[cpu.Node] = true
+	}
+	return len(nodes)
+}
@@ -0,0 +1,122 @@
+package hardware
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// writeTopology creates the sysfs topology of a two-socket machine with
+// two cores per socket and two threads per core, one NUMA node per socket.
+// Like Linux, it numbers the first thread of every core before the second.
+func writeTopology(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, root, "online", "0-7")
+	for cpu := 0; cpu < 8; cpu++ {
+		pkg, core := cpu%4/2, cpu%2
+		dir := fmt.Sprintf("cpu%d/", cpu)
+		writeFile(t, root, dir+"topology/physical_package_id", fmt.Sprint(pkg))
+		writeFile(t, root, dir+"topology/core_id", fmt.Sprint(core))
+		writeFile(t, root, dir+"topology/thread_siblings_list", fmt.Sprintf("%d,%d", cpu%4, cpu%4+4))
+		writeFile(t, root, dir+fmt.Sprintf("node%d/cpumap", pkg), "")
+	}
+	return root
+}
+
+func TestReadTopology(t *testing.T) {
+	topo := readTopology(writeTopology(t))
+	if topo.Threads() != 8 || topo.Cores() != 4 || topo.Packages() != 2 || topo.Nodes() != 2 {
+		t.Fatalf("topology = %d threads, %d cores, %d packages, %d nodes",
+			topo.Threads(), topo.Cores(), topo.Packages(), topo.Nodes())
+	}
+	want := CPU{ID: 6, Package: 1, Core: 0, Node: 1, Siblings: []int{2, 6}}
+	if !reflect.DeepEqual(topo.CPUs[6], want) {
+		t.Errorf("cpu6 = %+v, want %+v", topo.CPUs[6], want)
+	}
+
+	if topo := readTopology(t.TempDir()); len(topo.CPUs) != 0 {
+		t.Errorf("empty sysfs topology = %+v", topo)
+	}
+}
+
+func TestPlace(t *testing.T) {
+	topo := readTopology(writeTopology(t))
+	for p, want := range map[Placement][]int{
+		PlaceSpread: {0, 1, 2, 3, 4, 5, 6, 7},
+		PlacePack:   {0, 4, 1, 5, 2, 6, 3, 7},
+		PlaceNode:   {0, 1, 4, 5, 2, 3, 6, 7},
+		PlaceSocket: {0, 1, 4, 5, 2, 3, 6, 7},
+	} {
+		if got := topo.Place(p); !reflect.DeepEqual(got, want) {
+			t.Errorf("Place(%s) = %v, want %v", p, got, want)
+		}
+	}
+	if got := topo.Place("random"); got != nil {
+		t.Errorf("Place(random) = %v, want nil", got)
+	}
+
+	// Sub-NUMA clustering splits each socket into nodes, here one per core:
+	// node placement fills a node's SMT siblings before the next node
+	for i := range topo.CPUs {
+		topo.CPUs[i].Node = topo.CPUs[i].Package*2 + topo.CPUs[i].Core
+	}
+	for p, want := range map[Placement][]int{
+		PlaceNode:   {0, 4, 1, 5, 2, 6, 3, 7},
+		PlaceSocket: {0, 1, 4, 5, 2, 3, 6, 7},
+	} {
+		if got := topo.Place(p); !reflect.DeepEqual(got, want) {
+			t.Errorf("Place(%s) with a node per core = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestFormatCPUList(t *testing.T) {
+	for _, tc := range []struct {
+		cpus []int
+		want string
+	}{
+		{nil, ""},
+		{[]int{3}, "3"},
+		{[]int{4, 0, 1, 2, 2, 8, 10, 11}, "0-2,4,8,10-11"},
+	} {
+		if got := FormatCPUList(tc.cpus); got != tc.want {
+			t.Errorf("FormatCPUList(%v) = %q, want %q", tc.cpus, got, tc.want)
+		}
+		if got := FormatCPUList(parseCPUList(tc.want)); got != tc.want {
+			t.Errorf("round trip of %q = %q", tc.want, got)
+		}
+	}
+}
+
+func TestReadNodes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "online", "0-1")
+	writeFile(t, root, "node0/cpulist", "0-1,4-5")
+	writeFile(t, root, "node0/meminfo", "Node 0 MemTotal:       65536000 kB\nNode 0 MemFree:        1024 kB")
+	writeFile(t, root, "node0/distance", "10 21")
+	writeFile(t, root, "node1/cpulist", "2-3,6-7")
+	writeFile(t, root, "node1/distance", "21 10")
+
+	want := []Node{
+		{ID: 0, CPUs: []int{0, 1, 4, 5}, MemoryKB: 65536000, Distances: []int{10, 21}},
+		{ID: 1, CPUs: []int{2, 3, 6, 7}, Distances: []int{21, 10}},
+	}
+	if got := readNodes(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("readNodes = %+v, want %+v", got, want)
+	}
+	if got := readNodes(t.TempDir()); got != nil {
+		t.Errorf("readNodes without NUMA = %+v, want nil", got)
+	}
+
+	topo := readTopology(writeTopology(t))
+	if got := topo.OnNodes([]int{1}).Place(PlaceSpread); !reflect.DeepEqual(got, []int{2, 3, 6, 7}) {
+		t.Errorf("OnNodes(1) spread = %v", got)
+	}
+	if got := topo.NodeIDs(); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("NodeIDs = %v", got)
+	}
+	if got := topo.PackageOf()[6]; got != 1 {
+		t.Errorf("PackageOf()[6] = %d, want 1", got)
+	}
+}
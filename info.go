@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"goburn/burn"
+	"goburn/hardware"
+)
+
+// infoCommand prints the CPU topology and the limits on this process.
+func infoCommand(args []string) int {
+	fs := newFlagSet("info", "", `Show the machine inventory (host, kernel, CPU model, microcode,
+cpufreq governor and driver, SMT, turbo), the CPU topology (packages, cores,
+threads, NUMA nodes with their memory and distances), the Go runtime and
+cgroup v2 CPU limits, and the default worker count.`,
+		"  0    information printed\n"+
+			"  1    output could not be written\n"+
+			"  2    invalid flags\n")
+	asJSON := fs.Bool("json", false, "Print information as JSON")
+	perCPU := fs.Bool("cpus", false, "List every logical CPU")
+	if ok, code := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	inv := hardware.GetInventory()
+	power := hardware.GetPowerSettings()
+	topo := hardware.GetTopology()
+	nodes := hardware.GetNodes()
+	limits := hardware.GetCgroupLimits()
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(struct {
+			Inventory      hardware.Inventory     `json:"inventory"`
+			Power          hardware.PowerSettings `json:"power"`
+			Topology       hardware.Topology      `json:"topology"`
+			Nodes          []hardware.Node        `json:"numa_nodes"`
+			Cgroup         hardware.CgroupLimits  `json:"cgroup"`
+			GOMAXPROCS     int                    `json:"gomaxprocs"`
+			NumCPU         int                    `json:"num_cpu"`
+			DefaultWorkers int                    `json:"default_workers"`
+		}{inv, power, topo, nodes, limits, runtime.GOMAXPROCS(-1), runtime.NumCPU(), burn.DefaultWorkers(limits)})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "goburn info: %v\n", err)
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Host:\t%s\n", orUnknown(inv.Hostname))
+	fmt.Fprintf(tw, "Kernel:\t%s\n", orUnknown(inv.Kernel))
+	fmt.Fprintf(tw, "CPU:\t%s (%s)\n", orUnknown(inv.CPUModel), orUnknown(inv.Vendor))
+	fmt.Fprintf(tw, "Microcode:\t%s\n", orUnknown(inv.Microcode))
+	fmt.Fprintf(tw, "Cpufreq:\tgovernor %s, driver %s\n", orUnknown(inv.Governor), orUnknown(inv.Driver))
+	fmt.Fprintf(tw, "SMT:\t%s\n", orUnknown(inv.SMT))
+	fmt.Fprintf(tw, "Power:\t%s\n", orUnknown(power.String()))
+	fmt.Fprintf(tw, "Topology:\t%d packages, %d cores, %d threads, %d NUMA nodes\n",
+		topo.Packages(), topo.Cores(), topo.Threads(), topo.Nodes())
+	fmt.Fprintf(tw, "Go runtime:\tGOMAXPROCS=%d, NumCPU=%d\n", runtime.GOMAXPROCS(-1), runtime.NumCPU())
+	fmt.Fprintf(tw, "Cgroup:\t%s\n", describeCgroup(limits))
+	fmt.Fprintf(tw, "Default workers:\t%d\n", burn.DefaultWorkers(limits))
+	tw.Flush()
+
+	for _, w := range power.Warnings() {
+		fmt.Printf("warning: %s\n", w)
+	}
+
+	// A single node has nothing to tell apart
+	if len(nodes) > 1 {
+		fmt.Println()
+		tw = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "NODE\tCPUS\tMEMORY\tDISTANCES")
+		for _, n := range nodes {
+			memory := "unknown"
+			if n.MemoryKB > 0 {
+				memory = fmt.Sprintf("%.1f GiB", float64(n.MemoryKB)/(1<<20))
+			}
+			distances := make([]string, len(n.Distances))
+			for i, d := range n.Distances {
+				distances[i] = fmt.Sprint(d)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n",
+				n.ID, hardware.FormatCPUList(n.CPUs), memory, strings.Join(distances, " "))
+		}
+		tw.Flush()
+	}
+
+	if *perCPU {
+		fmt.Println()
+		tw = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "CPU\tPACKAGE\tCORE\tNODE\tSIBLINGS")
+		for _, cpu := range topo.CPUs {
+			siblings := make([]string, len(cpu.Siblings))
+			for i, s := range cpu.Siblings {
+				siblings[i] = fmt.Sprint(s)
+			}
+			fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%s\n",
+				cpu.ID, cpu.Package, cpu.Core, cpu.Node, strings.Join(siblings, ","))
+		}
+		tw.Flush()
+	}
+	return exitOK
+}
+
+// describeCgroup summarizes cgroup v2 CPU limits in one line.
+func describeCgroup(l hardware.CgroupLimits) string {
+	if l.Path == "" {
+		return "v2 not available"
+	}
+	parts := []string{l.Path}
+	if l.CPUQuota > 0 {
+		parts = append(parts, fmt.Sprintf("quota %.2f CPUs", l.CPUQuota))
+	} else {
+		parts = append(parts, "no quota")
+	}
+	if l.EffectiveCPUs > 0 {
+		parts = append(parts, fmt.Sprintf("cpuset %d CPUs", l.EffectiveCPUs))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// orUnknown returns s, or "unknown" if it is empty.
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -5,72 +5,183 @@
 //
 // Usage:
 //
-//	goburn [flags]
+//	goburn <command> [flags] [args]
 //
-// Flags:
+// Commands:
 //
-//	-duration duration
-//	    Test duration (default 50s)
-//	-graph
-//	    Enable dynamic TUI graph mode (default false)
+//	run      Burn CPUs while monitoring hardware (default)
+//	sensors  Dump every sensor reading with its sysfs path
+//	info     Show CPU topology and limits
+//	replay   Print the samples of a recorded report
+//	sweep    Measure throughput scaling over worker counts
+//	compare  Compare recorded reports against a baseline
+//	bench    Score the CPU with fixed, versioned kernels
+//	presets  List the available presets
+//	help     Show help for a command
+//
+// Running goburn with only flags is the same as "goburn run", so
+// "goburn -duration=1m" keeps working. Every command accepts -h.
+//
+// Exit codes:
+//
+//	0    success
+//	1    failure (runtime error or failed threshold)
+//	2    invalid command line or config
+//	130  interrupted by a signal
 //
 // In graph mode, you can:
 //   - Press '+' to increase workers
 //   - Press '-' to decrease workers
+//   - Press 'p' to pause, 't'/'T' to add or take off a minute, 'u' to run until quit
+//   - Press 'm' to drop a labelled marker on the timeline
+//   - Press 'z' to zoom and the arrow keys to scroll back in time
+//   - Press Tab to focus a panel and Enter to maximize it
+//   - Press 'c' to overlay ops, frequency and temperature on one graph
 //   - Press 'q' or Ctrl+C to quit
 //
 // Examples:
 //
 //	# Run for 1 minute with line output
-//	goburn -duration=1m
+//	goburn run -duration=1m
 //
 //	# Run with interactive TUI graphs
-//	goburn -duration=2m -graph
+//	goburn run -duration=2m -graph
+//
+//	# Comparable single-core and multi-core scores
+//	goburn bench
+//
+//	# Record a run and replay it later
+//	goburn run -report=run.json
+//	goburn replay run.json
+//
+//	# Find where throughput stops scaling, with a CSV of every step
+//	goburn sweep -steps=1,2,4,8,16 -csv=scaling.csv
+//
+//	# Compare two recorded runs, with an overlay of their temperatures
+//	goburn compare -chart=temp before.json after.json
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"runtime"
-	"time"
+	"io"
+	"os"
+	"strings"
+)
 
-	"goburn/ui"
-	"goburn/worker"
+// Exit codes shared by every command.
+const (
+	exitOK          = 0
+	exitFailure     = 1   // Runtime error or failed threshold
+	exitUsage       = 2   // Invalid command line or config
+	exitInterrupted = 130 // Stopped by SIGINT or SIGTERM
 )
 
+// command is a goburn subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands returns every subcommand in the order shown in help.
+func commands() []command {
+	return []command{
+		{"run", "Burn CPUs while monitoring hardware (default)", runCommand},
+		{"sensors", "Dump every sensor reading with its sysfs path", sensorsCommand},
+		{"info", "Show CPU topology and limits", infoCommand},
+		{"replay", "Print the samples of a recorded report", replayCommand},
+		{"sweep", "Measure throughput scaling over worker counts", sweepCommand},
+		{"compare", "Compare recorded reports against a baseline", compareCommand},
+		{"bench", "Score the CPU with fixed, versioned kernels", benchCommand},
+		{"presets", "List the available presets", presetsCommand},
+		{"help", "Show help for a command", helpCommand},
+	}
+}
+
 func main() {
-	// Parse command-line flags
-	duration := flag.Duration("duration", 50*time.Second, "Test duration")
-	graphMode := flag.Bool("graph", false, "Enable dynamic TUI graph mode")
-	flag.Parse()
+	os.Exit(dispatch(os.Args[1:]))
+}
 
-	// Initialize operation counter
-	var counter uint64
+// dispatch runs the command named by the first argument.
+func dispatch(args []string) int {
+	if len(args) == 0 {
+		return runCommand(nil)
+	}
 
-	// Determine initial worker count based on available CPUs
-	initialWorkers := runtime.GOMAXPROCS(-1)
-	fmt.Printf("runtime.GOMAXPROCS=%d so let's spawn %d goroutines\n",
-		initialWorkers, initialWorkers)
+	switch name := args[0]; {
+	case name == "-h" || name == "-help" || name == "--help":
+		printUsage(os.Stdout)
+		return exitOK
+	case strings.HasPrefix(name, "-"):
+		// Bare flags are passed to run for backwards compatibility
+		return runCommand(args)
+	}
 
-	// Wait briefly for output to be visible before TUI takes over
-	time.Sleep(100 * time.Millisecond)
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
 
-	start := time.Now()
+	fmt.Fprintf(os.Stderr, "goburn: unknown command %q\n\n", args[0])
+	printUsage(os.Stderr)
+	return exitUsage
+}
 
-	if *graphMode {
-		// Interactive TUI mode with graphs
-		ui.RunGraphMode(&counter, *duration, start, initialWorkers)
-	} else {
-		// Simple line mode
-		spawnSimpleWorkers(&counter, initialWorkers)
-		ui.RunLineMode(&counter, *duration, start)
+// printUsage lists every command.
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: goburn <command> [flags] [args]\n\nCommands:\n")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintf(w, "\nRun 'goburn help <command>' for details on a command.\n")
 }
 
-// spawnSimpleWorkers creates worker goroutines for line mode.
-// In line mode, workers run for the entire duration without dynamic control.
-func spawnSimpleWorkers(counter *uint64, count int) {
-	wp := worker.New(counter, count)
-	// Workers will run until program exits
-	_ = wp
+// helpCommand shows the help text of another command.
+func helpCommand(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return exitOK
+	}
+	for _, cmd := range commands() {
+		if cmd.name == args[0] && cmd.name != "help" {
+			return cmd.run([]string{"-h"})
+		}
+	}
+	fmt.Fprintf(os.Stderr, "goburn: unknown command %q\n", args[0])
+	return exitUsage
+}
+
+// newFlagSet creates a flag set whose help shows the command's usage line,
+// description, flags and exit codes.
+func newFlagSet(name, args, description, exitCodes string) *flag.FlagSet {
+	fs := flag.NewFlagSet("goburn "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintf(w, "Usage: %s\n\n%s\n", strings.TrimSpace("goburn "+name+" [flags] "+args), description)
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(w, "\nFlags:\n")
+			fs.PrintDefaults()
+		}
+		fmt.Fprintf(w, "\nExit codes:\n%s", exitCodes)
+	}
+	return fs
+}
+
+// parseFlags parses args and reports whether the command should continue.
+// If not, code is the exit code to return: 0 for -h, exitUsage otherwise.
+func parseFlags(fs *flag.FlagSet, args []string) (ok bool, code int) {
+	err := fs.Parse(args)
+	switch {
+	case err == nil:
+		return true, exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return false, exitOK
+	default:
+		return false, exitUsage
+	}
 }
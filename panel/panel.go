@@ -0,0 +1,11 @@
+// Package panel names the TUI panels. It sits below both config, which
+// validates the -panels setting, and ui, which draws the panels, so the
+// settings layer does not depend on the presentation layer.
+package panel
+
+// Names lists every TUI panel, in the default display order.
+var Names = []string{"ops", "freq", "temp", "fans", "power", "utilization", "per-core", "correlation"}
+
+// Defaults are the TUI panels shown when none are configured.
+// Panels without a data source on this machine are hidden anyway.
+var Defaults = []string{"ops", "freq", "temp", "fans", "power", "utilization"}
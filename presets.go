@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"goburn/config"
+)
+
+// presetsCommand prints every available preset and its settings.
+func presetsCommand(args []string) int {
+	fs := newFlagSet("presets", "", `List the built-in presets and those defined in the config file,
+with the settings each one applies. Use one with 'goburn run -preset=<name>'.`,
+		"  0    presets listed\n"+
+			"  2    invalid flags or config file\n")
+	configPath := fs.String("config", "", "Config file with named presets (default "+config.DefaultFile+")")
+	if ok, code := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn presets: %v\n", err)
+		return exitUsage
+	}
+
+	for _, p := range cfg.Presets() {
+		fmt.Printf("%s (%s)\n", p.Name, p.Source)
+		if p.Description != "" {
+			fmt.Printf("    %s\n", p.Description)
+		}
+		for _, v := range p.Values() {
+			fmt.Printf("    %s\n", v)
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"goburn/burn"
+	"goburn/ui"
+)
+
+// replayCommand prints the samples of a report written by 'run -report'.
+func replayCommand(args []string) int {
+	fs := newFlagSet("replay", "<report.json>", `Print the samples of a report recorded with 'goburn run -report=<file>'
+in line mode format, followed by the run summary.`,
+		"  0    report replayed\n"+
+			"  1    the report could not be read or replayed\n"+
+			"  2    invalid flags or missing report argument\n"+
+			"  130  interrupted by SIGINT or SIGTERM\n")
+	speed := fs.Float64("speed", 0, "Pace output at this multiple of real time (0 = print at once)")
+	formatName := fs.String("format", "text", "Output format: text, json or logfmt")
+	if ok, code := parseFlags(fs, args); !ok {
+		return code
+	}
+	format, err := ui.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn replay: %v\n", err)
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	report, err := burn.ReadReport(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn replay: %v\n", err)
+		return exitFailure
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	err = ui.RunReplayMode(ctx, report, *speed, format)
+	if errors.Is(err, context.Canceled) {
+		return exitInterrupted
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn replay: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"goburn/burn"
+	"goburn/config"
+	"goburn/hardware"
+	"goburn/panel"
+	"goburn/ui"
+	"goburn/worker"
+)
+
+// runCommand burns CPUs in line or graph mode. This is today's default
+// behaviour of goburn.
+func runCommand(args []string) int {
+	// Flag defaults come from config.Defaults
+	defaults := config.Defaults()
+	fs := newFlagSet("run", "", `Spawn CPU-intensive workers and print or graph throughput, CPU
+frequency, temperature and fan speeds until the duration elapses.
+
+In graph mode, press '+'/'-' to change the worker count, 'p' to pause,
+'t'/'T' to change the run time, 'tab' and 'enter' to pick and maximize
+a panel, and 'q' to quit.`,
+		"  0    run completed and passed every threshold\n"+
+			"  1    a threshold failed or the report could not be written\n"+
+			"  2    invalid flags, config file or preset\n"+
+			"  130  interrupted by SIGINT or SIGTERM\n")
+	fs.Duration("duration", defaults.Duration, "Test duration")
+	fs.Duration("warmup", defaults.Warmup, "Run this long before measuring; warmup samples are left out of the summary and thresholds")
+	fs.Duration("until-steady", defaults.UntilSteady, "End this long after ops/s and temperature plateau, measuring from then on; -duration still caps the run")
+	fs.Duration("interval", defaults.Interval, "Sample interval; ops are still reported per second")
+	fs.Bool("graph", defaults.Graph, "Enable dynamic TUI graph mode")
+	fs.Int("workers", defaults.Workers, "Worker count (0 = CPUs allowed by GOMAXPROCS and cgroup)")
+	fs.String("workload", defaults.Workload, workloadUsage())
+	fs.String("placement", defaults.Placement, "Pin workers to logical CPUs: "+strings.Join(config.PlacementNames, ", "))
+	fs.String("nodes", "", "Comma-separated NUMA nodes to run workers on (default all)")
+	fs.String("mem-nodes", "", "Comma-separated NUMA nodes to hold worker memory, round-robin (default each worker's own)")
+	fs.Float64("max-temp", defaults.MaxTemp, "Fail if the temperature exceeds this many °C")
+	fs.Float64("min-ops", defaults.MinOps, "Fail if average throughput is below this many M ops/s")
+	fs.String("report", defaults.Report, "Write a JSON report of the run to this file")
+	fs.String("format", defaults.Format, "Line mode output format: text, json or logfmt")
+	fs.String("panels", strings.Join(defaults.Panels, ","), "TUI panels in display order: "+strings.Join(panel.Names, ", "))
+	fs.String("theme", defaults.Theme, "TUI color theme: "+strings.Join(config.ThemeNames, ", "))
+	configPath := fs.String("config", "", "Config file with named presets (default "+config.DefaultFile+")")
+	presetName := fs.String("preset", "", "Apply a named preset; explicit flags override it")
+	if ok, code := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "goburn run: unexpected argument %q\n", fs.Arg(0))
+		return exitUsage
+	}
+
+	settings, err := loadSettings(fs, *configPath, *presetName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn run: %v\n", err)
+		return exitUsage
+	}
+
+	if settings.Duration > 0 && settings.Warmup >= settings.Duration {
+		fmt.Fprintf(os.Stderr, "goburn run: warmup %s leaves nothing of the %s duration to measure\n",
+			settings.Warmup, settings.Duration)
+		return exitUsage
+	}
+
+	if settings.UntilSteady > 0 && settings.Duration > 0 && settings.Duration < burn.DefaultSteadyWindow+settings.UntilSteady {
+		fmt.Fprintf(os.Stderr, "warning: -duration=%s may end the run before -until-steady does; -duration=0 waits for steady state\n",
+			settings.Duration)
+	}
+
+	format, _ := ui.ParseFormat(settings.Format)
+	theme, _ := ui.LookupTheme(settings.Theme)
+
+	// Machine-readable formats keep stdout for records only
+	info := os.Stdout
+	if format != ui.FormatText {
+		info = os.Stderr
+	}
+
+	// Determine initial worker count based on available CPUs,
+	// capped by the cgroup CPU quota or cpuset when running in a container
+	limits := hardware.GetCgroupLimits()
+	initialWorkers := settings.Workers
+	if initialWorkers == 0 {
+		initialWorkers = burn.DefaultWorkers(limits)
+		if procs := runtime.GOMAXPROCS(-1); initialWorkers < procs {
+			fmt.Fprintf(info, "runtime.GOMAXPROCS=%d but cgroup allows %d CPUs so let's spawn %d goroutines\n",
+				procs, initialWorkers, initialWorkers)
+		} else {
+			fmt.Fprintf(info, "runtime.GOMAXPROCS=%d so let's spawn %d goroutines\n",
+				initialWorkers, initialWorkers)
+		}
+	} else {
+		fmt.Fprintf(info, "spawning %d goroutines\n", initialWorkers)
+	}
+
+	workload, _ := worker.LookupWorkload(settings.Workload)
+	binding := burn.Binding{
+		Nodes:     settings.Nodes,
+		MemNodes:  settings.MemNodes,
+		CrossNode: workload.CrossNode,
+	}
+	if settings.Placement != "none" {
+		binding.Placement = hardware.Placement(settings.Placement)
+	}
+	poolOptions := []worker.Option{worker.WithGOMAXPROCS(), worker.WithWorkload(workload)}
+	// Graph mode builds the pool itself, line mode leaves it to burn.Run
+	graphOptions, err := binding.Options(poolOptions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn run: %v\n", err)
+		return exitUsage
+	}
+	if binding.Pinned() {
+		topo := hardware.GetTopology()
+		if len(topo.CPUs) == 0 {
+			fmt.Fprintf(os.Stderr, "warning: no CPU topology in sysfs, workers are left unpinned\n")
+		} else {
+			fmt.Fprintf(info, "pinning workers by %s, in CPU order %s\n", binding, formatCPUOrder(binding.Order(topo)))
+			if workload.CrossNode && len(topo.NodeIDs()) < 2 {
+				fmt.Fprintf(os.Stderr, "warning: a single NUMA node, -workload=%s measures local memory\n", settings.Workload)
+			}
+		}
+	}
+
+	// Wait briefly for output to be visible before TUI takes over
+	time.Sleep(100 * time.Millisecond)
+
+	// Stop workers cleanly on Ctrl+C or SIGTERM
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var report burn.Report
+	if settings.Graph {
+		// Interactive TUI mode with graphs
+		// Read the power settings before the burn can change them
+		power := hardware.GetPowerSettings()
+		var counter uint64
+		start := time.Now()
+		wp := worker.New(ctx, &counter, initialWorkers, graphOptions...)
+		report, err = ui.RunGraphMode(ctx, wp, ui.GraphConfig{
+			Duration:    settings.Duration,
+			Binding:     binding,
+			Warmup:      settings.Warmup,
+			UntilSteady: settings.UntilSteady,
+			Start:       start,
+			Interval:    settings.Interval,
+			Limits:      limits,
+			Power:       power,
+			Panels:      settings.Panels,
+			Theme:       theme,
+		})
+		wp.Stop()
+	} else {
+		// Simple line mode; an interrupted run still writes its report
+		report, err = ui.RunLineMode(ctx, burn.Config{
+			Duration:    settings.Duration,
+			Warmup:      settings.Warmup,
+			UntilSteady: settings.UntilSteady,
+			Interval:    settings.Interval,
+			Workers:     initialWorkers,
+			Binding:     binding,
+			PoolOptions: poolOptions,
+		}, format)
+	}
+
+	if settings.Report != "" {
+		if err := report.WriteFile(settings.Report); err != nil {
+			fmt.Fprintf(os.Stderr, "goburn run: writing report: %v\n", err)
+			return exitFailure
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return exitInterrupted
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn run: %v\n", err)
+		return exitFailure
+	}
+
+	thresholds := burn.Thresholds{
+		MaxTemp:      settings.MaxTemp,
+		MinOpsPerSec: settings.MinOps * 1_000_000,
+	}
+	if err := thresholds.Check(report.Summary); err != nil {
+		fmt.Fprintf(os.Stderr, "FAIL: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// workloadUsage describes the -workload flag, one line per workload.
+func workloadUsage() string {
+	usage := "CPU kernel run by workers:"
+	for _, w := range worker.Workloads() {
+		usage += fmt.Sprintf("\n  %s: %s", w.Name, w.Description)
+	}
+	return usage
+}
+
+// formatCPUOrder lists the first CPUs of a placement order.
+func formatCPUOrder(order []int) string {
+	const shown = 8
+	parts := make([]string, 0, shown+1)
+	for i, cpu := range order {
+		if i == shown {
+			parts = append(parts, "...")
+			break
+		}
+		parts = append(parts, strconv.Itoa(cpu))
+	}
+	return strings.Join(parts, ",")
+}
+
+// loadSettings combines defaults, the selected preset and explicit flags,
+// in increasing order of precedence. An explicit -config file is loaded,
+// and so checked for errors, even when no preset is selected.
+func loadSettings(fs *flag.FlagSet, configPath, presetName string) (config.Settings, error) {
+	settings := config.Defaults()
+
+	if presetName != "" || configPath != "" {
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			return settings, err
+		}
+		if presetName != "" {
+			preset, err := cfg.Preset(presetName)
+			if err != nil {
+				return settings, err
+			}
+			if err := preset.Apply(&settings); err != nil {
+				return settings, err
+			}
+		}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err == nil && f.Name != "config" && f.Name != "preset" {
+			err = settings.Set(f.Name, f.Value.String())
+		}
+	})
+	return settings, err
+}
+
+// loadConfig loads the given config file, or the default one if present.
+func loadConfig(path string) (*config.Config, error) {
+	if path == "" {
+		return config.Load(config.DefaultFile, false)
+	}
+	return config.Load(path, true)
+}
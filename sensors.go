@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"goburn/hardware"
+)
+
+// sensorsCommand dumps every sensor reading once, with its sysfs path.
+func sensorsCommand(args []string) int {
+	fs := newFlagSet("sensors", "", `Read every CPU frequency, temperature, fan and cgroup sensor goburn
+can find and print it with the sysfs file it came from. Sensors marked
+with '*' are the ones used by 'goburn run'.`,
+		"  0    at least one sensor was found\n"+
+			"  1    no sensor could be read\n"+
+			"  2    invalid flags\n")
+	asJSON := fs.Bool("json", false, "Print sensors as a JSON array")
+	if ok, code := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	sensors := hardware.ListSensors()
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(sensors); err != nil {
+			fmt.Fprintf(os.Stderr, "goburn sensors: %v\n", err)
+			return exitFailure
+		}
+	} else {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "\tKIND\tNAME\tLABEL\tVALUE\tPATH")
+		for _, s := range sensors {
+			used := ""
+			if s.Used {
+				used = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.1f %s\t%s\n",
+				used, s.Kind, s.Name, s.Label, s.Value, s.Unit, s.Path)
+		}
+		tw.Flush()
+	}
+
+	if len(sensors) == 0 {
+		fmt.Fprintln(os.Stderr, "goburn sensors: no sensors found")
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"goburn/burn"
+	"goburn/config"
+	"goburn/hardware"
+	"goburn/ui"
+	"goburn/worker"
+)
+
+// sweepCommand measures throughput at increasing worker counts.
+func sweepCommand(args []string) int {
+	fs := newFlagSet("sweep", "", `Step the worker count from 1 to -max, or over the -steps list, and
+measure each step for -hold after letting it settle for -warmup. Print
+ops/s, temperature, frequency and package power of every step, with the
+throughput per worker and the scaling efficiency: the throughput per
+worker in percent of that of the first step. Efficiency dropping shows
+where SMT siblings, memory bandwidth or thermal limits stop throughput
+from scaling. With -placement, each step pins its new worker to the next
+logical CPU of the placement order, so that spread and pack sweeps show
+the cost of sharing cores between SMT siblings. With -nodes, the sweep
+stays on the CPUs of the given NUMA nodes.`,
+		"  0    sweep completed\n"+
+			"  1    the sweep failed, or its CSV or report could not be written\n"+
+			"  2    invalid flags\n"+
+			"  130  interrupted by SIGINT or SIGTERM\n")
+	maxWorkers := fs.Int("max", 0, "Step from 1 to this many workers (0 = CPUs allowed by GOMAXPROCS and cgroup)")
+	stepsFlag := fs.String("steps", "", "Comma-separated worker counts to measure instead, such as 1,2,4,8")
+	warmup := fs.Duration("warmup", 0, "Settling time after each worker count change (default 5s)")
+	hold := fs.Duration("hold", 0, "Measured time of each step (default 10s)")
+	interval := fs.Duration("interval", 0, "Sample interval (default 1s)")
+	workloadName := fs.String("workload", worker.DefaultWorkload, workloadUsage())
+	placementName := fs.String("placement", config.DefaultPlacement, "Pin workers to logical CPUs: "+strings.Join(config.PlacementNames, ", "))
+	nodesFlag := fs.String("nodes", "", "Comma-separated NUMA nodes to run workers on (default all)")
+	memNodesFlag := fs.String("mem-nodes", "", "Comma-separated NUMA nodes to hold worker memory, round-robin (default each worker's own)")
+	csvPath := fs.String("csv", "", "Write the per-step results as CSV to this file")
+	reportPath := fs.String("report", "", "Write a JSON report of the whole sweep to this file")
+	if ok, code := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "goburn sweep: unexpected argument %q\n", fs.Arg(0))
+		return exitUsage
+	}
+	if *maxWorkers < 0 || *warmup < 0 || *hold < 0 || *interval < 0 {
+		fmt.Fprintln(os.Stderr, "goburn sweep: -max, -warmup, -hold and -interval must not be negative")
+		return exitUsage
+	}
+	workload, ok := worker.LookupWorkload(*workloadName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "goburn sweep: unknown workload %q\n", *workloadName)
+		return exitUsage
+	}
+
+	binding := burn.Binding{CrossNode: workload.CrossNode}
+	if *placementName != "none" {
+		var ok bool
+		if binding.Placement, ok = hardware.ParsePlacement(*placementName); !ok {
+			fmt.Fprintf(os.Stderr, "goburn sweep: unknown placement %q\n", *placementName)
+			return exitUsage
+		}
+	}
+	var err error
+	if binding.Nodes, err = config.ParseNodes(*nodesFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "goburn sweep: -nodes: %v\n", err)
+		return exitUsage
+	}
+	if binding.MemNodes, err = config.ParseNodes(*memNodesFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "goburn sweep: -mem-nodes: %v\n", err)
+		return exitUsage
+	}
+	// Check the binding up front, so errors of RunSweep are runtime failures
+	poolOptions := []worker.Option{worker.WithGOMAXPROCS(), worker.WithWorkload(workload)}
+	if _, err := binding.Options(poolOptions); err != nil {
+		fmt.Fprintf(os.Stderr, "goburn sweep: %v\n", err)
+		return exitUsage
+	}
+
+	var steps []int
+	switch {
+	case *stepsFlag != "":
+		if steps, err = parseSteps(*stepsFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "goburn sweep: -steps: %v\n", err)
+			return exitUsage
+		}
+	case *maxWorkers > 0:
+		for n := 1; n <= *maxWorkers; n++ {
+			steps = append(steps, n)
+		}
+	default:
+		steps = burn.DefaultSweepSteps(hardware.GetCgroupLimits())
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	result, err := ui.RunSweepMode(ctx, burn.SweepConfig{
+		Steps:       steps,
+		Warmup:      *warmup,
+		Hold:        *hold,
+		Interval:    *interval,
+		Binding:     binding,
+		PoolOptions: poolOptions,
+	})
+
+	// An interrupted sweep still writes the steps it completed
+	if *csvPath != "" {
+		if err := writeSweepCSV(*csvPath, result); err != nil {
+			fmt.Fprintf(os.Stderr, "goburn sweep: writing CSV: %v\n", err)
+			return exitFailure
+		}
+	}
+	if *reportPath != "" {
+		if err := result.Report.WriteFile(*reportPath); err != nil {
+			fmt.Fprintf(os.Stderr, "goburn sweep: writing report: %v\n", err)
+			return exitFailure
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return exitInterrupted
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goburn sweep: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// parseSteps parses a comma-separated list of worker counts.
+func parseSteps(list string) ([]int, error) {
+	var steps []int
+	for _, field := range strings.Split(list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid worker count %q", field)
+		}
+		steps = append(steps, n)
+	}
+	return steps, nil
+}
+
+// writeSweepCSV saves the per-step results of a sweep as CSV.
+func writeSweepCSV(path string, result burn.SweepResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := result.WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
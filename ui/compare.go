@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guptarohit/asciigraph"
+
+	"goburn/burn"
+)
+
+// RunCompareMode prints how every report after the first differs from the
+// first, the baseline, on each metric both measured. With a chart metric
+// name, it also plots that metric of every run on one time axis.
+func RunCompareMode(names []string, reports []burn.Report, chart string) {
+	for i, r := range reports {
+		role := "run"
+		if i == 0 {
+			role = "baseline"
+		}
+		fmt.Printf("%s %d: %s | %s\n", role, i, names[i], describeRun(r))
+	}
+
+	base := reports[0]
+	for i, other := range reports[1:] {
+		fmt.Printf("\n%s vs %s\n", names[i+1], names[0])
+		deltas := burn.Compare(base, other)
+		if len(deltas) == 0 {
+			fmt.Println("  no steady-state samples in common")
+			continue
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "  METRIC\tBASELINE\tRUN\tCHANGE\tHINT")
+		for _, d := range deltas {
+			fmt.Fprintln(tw, formatDelta(d))
+		}
+		tw.Flush()
+	}
+
+	if chart != "" {
+		if m, ok := burn.LookupMetric(chart); ok {
+			fmt.Println()
+			fmt.Println(renderOverlay(names, reports, m, 70, 12))
+		}
+	}
+}
+
+// describeRun summarizes when and where a run happened for the header.
+func describeRun(r burn.Report) string {
+	parts := []string{r.Start.Format("2006-01-02 15:04"), r.End.Sub(r.Start).Round(time.Second).String(),
+		fmt.Sprintf("%d workers", r.Workers)}
+	if binding := r.Binding.String(); binding != "" {
+		parts = append(parts, binding)
+	}
+	if machine := r.Inventory.Short(); machine != "" {
+		parts = append(parts, machine)
+	}
+	if r.Warmup > 0 {
+		parts = append(parts, "warmup "+r.Warmup.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDelta renders one row of the comparison table, tab-separated.
+func formatDelta(d burn.Delta) string {
+	scale := d.Metric.Scale
+	hint := d.Hint
+	if d.Hint == burn.Significant || d.Hint == burn.Likely {
+		// Say whether the change is for the better
+		if (d.Change > 0) == d.Metric.HigherIsBetter {
+			hint += " ↑ better"
+		} else {
+			hint += " ↓ worse"
+		}
+	}
+	return fmt.Sprintf("  %s\t%.1f\t%.1f\t%+.1f (%+.1f%%)\t%s",
+		d.Metric.Label, d.Base.Avg/scale, d.Other.Avg/scale,
+		d.Change/scale, d.ChangePct, hint)
+}
+
+// overlayColors tell runs apart on the overlay chart, baseline first.
+// They are left out when the NO_COLOR environment variable is set.
+var overlayColors = []asciigraph.AnsiColor{
+	asciigraph.Default, asciigraph.Gold, asciigraph.DarkTurquoise,
+	asciigraph.OrangeRed, asciigraph.MediumOrchid, asciigraph.LimeGreen,
+}
+
+// renderOverlay plots metric m of every run against elapsed time, each run
+// averaged into width time slots of the longest run. Slots without a value
+// are left as gaps.
+func renderOverlay(names []string, reports []burn.Report, m burn.Metric, width, height int) string {
+	var end time.Duration
+	for _, r := range reports {
+		if n := len(r.Samples); n > 0 {
+			end = max(end, r.Samples[n-1].Elapsed)
+		}
+	}
+	if end == 0 {
+		return "no samples to chart"
+	}
+
+	var data [][]float64
+	var colors []asciigraph.AnsiColor
+	var legends []string
+	for i, r := range reports {
+		sums, counts := make([]float64, width), make([]int, width)
+		for _, s := range r.Samples {
+			if v, ok := m.Value(s); ok {
+				slot := min(int(float64(s.Elapsed)/float64(end)*float64(width)), width-1)
+				sums[slot] += v / m.Scale
+				counts[slot]++
+			}
+		}
+		values := make([]float64, width)
+		seen := false
+		for j := range values {
+			values[j] = math.NaN()
+			if counts[j] > 0 {
+				values[j] = sums[j] / float64(counts[j])
+				seen = true
+			}
+		}
+		if !seen {
+			continue
+		}
+		data = append(data, values)
+		colors = append(colors, overlayColors[i%len(overlayColors)])
+		legends = append(legends, names[i])
+	}
+	if len(data) == 0 {
+		return fmt.Sprintf("no %s samples to chart", m.Label)
+	}
+
+	options := []asciigraph.Option{
+		asciigraph.Height(height),
+		asciigraph.Width(width),
+		asciigraph.Caption(m.Label + " over " + formatElapsed(end)),
+	}
+	// Legends need colors to tell the lines apart
+	if os.Getenv("NO_COLOR") == "" {
+		options = append(options, asciigraph.SeriesColors(colors...), asciigraph.SeriesLegends(legends...))
+	}
+	plot := asciigraph.PlotMany(data, options...)
+	graph, legend, _ := strings.Cut(plot, "\n\n")
+	if legend == "" {
+		legend = "runs: " + strings.Join(legends, ", ")
+	}
+	return graph + "\n" + renderTimeAxis(graph, 0, end, nil, nil) + "\n" + legend
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+)
+
+// correlationSeries is one line of the correlation panel.
+type correlationSeries struct {
+	legend string
+	color  asciigraph.AnsiColor
+	values []float64 // One per ops bucket, NaN where the metric is missing
+}
+
+// correlationTempMax is the temperature at the top of the correlation
+// panel. It is above the maximum junction temperature of current CPUs
+// (100-115°C), so a throttling CPU still shows below the top.
+const correlationTempMax = 125.0
+
+// correlationData aligns ops/s, CPU frequency and temperature on the ops
+// buckets in view. Ops are normalized to percent of their peak in view and
+// temperature to percent of correlationTempMax, so that every series shares
+// the 0-100 scale of frequency. Metrics without a data source are left out.
+func correlationData(h *history, v graphView, colors [3]asciigraph.AnsiColor) []correlationSeries {
+	ops := h.ops.window(v.from, v.to, v.width)
+	if len(ops) == 0 {
+		return nil
+	}
+
+	peak := 0.0
+	for _, b := range ops {
+		peak = max(peak, b.avg())
+	}
+	opsPct := make([]float64, len(ops))
+	for i, b := range ops {
+		if peak > 0 {
+			opsPct[i] = b.avg() / peak * 100
+		}
+	}
+	series := []correlationSeries{{legend: "ops (% of peak)", color: colors[0], values: opsPct}}
+
+	// Match the other metrics to the ops bucket with the same start
+	align := func(buckets []bucket) []float64 {
+		byStart := make(map[int64]float64, len(buckets))
+		for _, b := range buckets {
+			byStart[int64(b.start)] = b.avg()
+		}
+		values := make([]float64, len(ops))
+		for i, b := range ops {
+			v, ok := byStart[int64(b.start)]
+			if !ok {
+				v = math.NaN()
+			}
+			values[i] = v
+		}
+		return values
+	}
+	if cpu := h.cpu.window(v.from, v.to, v.width); len(cpu) > 0 {
+		series = append(series, correlationSeries{legend: "freq (% of max)", color: colors[1], values: align(cpu)})
+	}
+	if temp := h.temp.window(v.from, v.to, v.width); len(temp) > 0 {
+		values := align(temp)
+		for i, v := range values {
+			values[i] = v * 100 / correlationTempMax
+		}
+		series = append(series, correlationSeries{
+			legend: fmt.Sprintf("temp (%% of %.0f°C)", correlationTempMax),
+			color:  colors[2],
+			values: values,
+		})
+	}
+	return series
+}
+
+// renderCorrelationPanel creates a panel that overlays normalized ops/s,
+// CPU frequency and temperature on one time axis, so that throttling
+// shows up as frequency dropping with ops while temperature peaks.
+func (m Model) renderCorrelationPanel(title string, height, width int, focused bool) string {
+	borderColor := m.theme.Accent
+	panelStyle := m.panelStyle(borderColor, height, width, focused)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(borderColor).
+		Background(m.theme.Background).
+		Padding(0, 1)
+
+	axisStyle := lipgloss.NewStyle().
+		Foreground(m.theme.Muted)
+
+	var g strings.Builder
+	g.WriteString(labelStyle.Render(title))
+	g.WriteString("\n\n")
+
+	v := m.graphView(width)
+	series := correlationData(m.history, v, m.theme.Series)
+	if len(series) == 0 || len(series[0].values) < 2 {
+		waitStyle := lipgloss.NewStyle().
+			Foreground(m.theme.Muted).
+			Italic(true)
+		g.WriteString(waitStyle.Render("⏳ Collecting data..."))
+		return panelStyle.Render(g.String())
+	}
+
+	data := make([][]float64, len(series))
+	colors := make([]asciigraph.AnsiColor, len(series))
+	legends := make([]string, len(series))
+	for i, s := range series {
+		data[i], colors[i], legends[i] = s.values, s.color, s.legend
+	}
+
+	// The legend takes the place of the other panels' value line
+	plot := asciigraph.PlotMany(data,
+		asciigraph.Height(height),
+		asciigraph.Width(width),
+		asciigraph.LowerBound(0),
+		asciigraph.UpperBound(100),
+		asciigraph.SeriesColors(colors...),
+		asciigraph.SeriesLegends(legends...))
+
+	// Put the time axis between the plot and its legend
+	graph, legend, _ := strings.Cut(plot, "\n\n")
+	graph = drawMarkers(graph, v.from, v.to, m.markers)
+	g.WriteString(graph)
+	g.WriteString("\n")
+	g.WriteString(axisStyle.Render(renderTimeAxis(graph, v.from, v.to, m.pausePeriods(), m.markers)))
+	g.WriteString("\n")
+	g.WriteString(legend)
+
+	return panelStyle.Render(g.String())
+}
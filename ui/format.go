@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"goburn/burn"
+)
+
+// Format selects how line mode renders each sample.
+type Format string
+
+const (
+	FormatText   Format = "text"   // Human-readable "[5s] ops=123M/s | ..." lines
+	FormatJSON   Format = "json"   // One LineRecord JSON object per line
+	FormatLogfmt Format = "logfmt" // LineRecord fields as key=value pairs
+)
+
+// Formats lists every supported output format.
+var Formats = []Format{FormatText, FormatJSON, FormatLogfmt}
+
+// ParseFormat validates a format name.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (want text, json or logfmt)", name)
+}
+
+// LineSchemaVersion is the version of the LineRecord schema. It changes
+// only when fields are renamed, removed or change meaning.
+const LineSchemaVersion = 1
+
+// LineRecord is the machine-readable form of one sample. It carries the
+// values the TUI shows, with units in the field names. Metrics the
+// machine does not expose are null in JSON and omitted in logfmt.
+type LineRecord struct {
+	Version        int      `json:"version"`
+	ElapsedSec     float64  `json:"elapsed_s"`
+	IntervalSec    float64  `json:"interval_s"` // Time covered by this record
+	DurationSec    float64  `json:"duration_s"` // 0 for unlimited runs
+	Workers        int      `json:"workers"`
+	OpsPerSec      float64  `json:"ops_per_sec"`
+	CPUFreqMHz     *int     `json:"cpu_freq_mhz"`
+	CPUFreqMaxMHz  *int     `json:"cpu_freq_max_mhz"`
+	CPUFreqPct     *float64 `json:"cpu_freq_pct"`
+	TemperatureC   *float64 `json:"temperature_c"`
+	FanAvgRPM      *int     `json:"fan_avg_rpm"`
+	ThrottledMs    *int64   `json:"throttled_ms"`    // Cgroup throttling during the interval
+	PackageWatts   *float64 `json:"package_watts"`   // RAPL power of all packages
+	UtilizationPct *float64 `json:"utilization_pct"` // Busy time of all CPUs
+	// Sockets has one record per physical package, null on machines with
+	// a single one
+	Sockets []SocketRecord `json:"sockets"`
+	Warmup  bool           `json:"warmup,omitempty"` // Left out of the summary
+}
+
+// SocketRecord is the machine-readable form of the part of a sample on
+// one physical package. Metrics the package does not expose are null.
+type SocketRecord struct {
+	Package      int      `json:"package"`       // physical_package_id
+	OpsPerSec    *float64 `json:"ops_per_sec"`   // Ops of the workers pinned to the package, null if unpinned
+	CPUFreqMHz   *int     `json:"cpu_freq_mhz"`  // Mean frequency of the package's CPUs
+	TemperatureC *float64 `json:"temperature_c"` // Package temperature
+	PackageWatts *float64 `json:"package_watts"` // RAPL power of the package
+}
+
+// NewLineRecord converts a sample of a run lasting duration.
+func NewLineRecord(s burn.Sample, duration time.Duration) LineRecord {
+	rec := LineRecord{
+		Version:     LineSchemaVersion,
+		ElapsedSec:  s.Elapsed.Round(time.Millisecond).Seconds(),
+		IntervalSec: s.Interval.Round(time.Millisecond).Seconds(),
+		DurationSec: duration.Seconds(),
+		Workers:     s.Workers,
+		OpsPerSec:   math.Round(s.OpsPerSec),
+		Warmup:      s.Warmup,
+	}
+	if s.Stats.CPUFreqMax > 0 {
+		rec.CPUFreqMHz = &s.Stats.CPUFreqCur
+		rec.CPUFreqMaxMHz = &s.Stats.CPUFreqMax
+		rec.CPUFreqPct = &s.Stats.CPUFreqPct
+	}
+	if s.Stats.Temperature > 0 {
+		rec.TemperatureC = &s.Stats.Temperature
+	}
+	if len(s.Stats.FanRPMs) > 0 {
+		avg := 0
+		for _, rpm := range s.Stats.FanRPMs {
+			avg += rpm
+		}
+		avg /= len(s.Stats.FanRPMs)
+		rec.FanAvgRPM = &avg
+	}
+	if s.Stats.ThrottledUSec >= 0 {
+		ms := s.Throttled.Milliseconds()
+		rec.ThrottledMs = &ms
+	}
+	// Like the TUI, treat zero power and utilization as unavailable: the
+	// first sample has no baseline to compute them from
+	if s.PackageWatts > 0 {
+		rec.PackageWatts = &s.PackageWatts
+	}
+	if s.Utilization > 0 {
+		rec.UtilizationPct = &s.Utilization
+	}
+	for _, sock := range s.Sockets {
+		sr := SocketRecord{Package: sock.Package}
+		if sock.OpsPerSec > 0 {
+			ops := math.Round(sock.OpsPerSec)
+			sr.OpsPerSec = &ops
+		}
+		if sock.CPUFreqMHz > 0 {
+			sr.CPUFreqMHz = &sock.CPUFreqMHz
+		}
+		if sock.Temperature > 0 {
+			sr.TemperatureC = &sock.Temperature
+		}
+		if sock.PackageWatts > 0 {
+			sr.PackageWatts = &sock.PackageWatts
+		}
+		rec.Sockets = append(rec.Sockets, sr)
+	}
+	return rec
+}
+
+// formatSample renders a sample in the given format, without a newline.
+func formatSample(format Format, s burn.Sample, duration time.Duration) string {
+	switch format {
+	case FormatJSON:
+		data, _ := json.Marshal(NewLineRecord(s, duration))
+		return string(data)
+	case FormatLogfmt:
+		return formatLogfmt(NewLineRecord(s, duration))
+	}
+	return formatLine(s)
+}
+
+// formatLogfmt renders a record as logfmt, in schema field order. Socket
+// fields are prefixed with the package, as in "socket1_temperature_c".
+func formatLogfmt(rec LineRecord) string {
+	float := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	parts := []string{
+		"version=" + strconv.Itoa(rec.Version),
+		"elapsed_s=" + float(rec.ElapsedSec),
+		"interval_s=" + float(rec.IntervalSec),
+		"duration_s=" + float(rec.DurationSec),
+		"workers=" + strconv.Itoa(rec.Workers),
+		"ops_per_sec=" + strconv.FormatFloat(rec.OpsPerSec, 'f', 0, 64),
+	}
+	if rec.CPUFreqMHz != nil {
+		parts = append(parts,
+			"cpu_freq_mhz="+strconv.Itoa(*rec.CPUFreqMHz),
+			"cpu_freq_max_mhz="+strconv.Itoa(*rec.CPUFreqMaxMHz),
+			"cpu_freq_pct="+strconv.FormatFloat(*rec.CPUFreqPct, 'f', 1, 64))
+	}
+	if rec.TemperatureC != nil {
+		parts = append(parts, "temperature_c="+float(*rec.TemperatureC))
+	}
+	if rec.FanAvgRPM != nil {
+		parts = append(parts, "fan_avg_rpm="+strconv.Itoa(*rec.FanAvgRPM))
+	}
+	if rec.ThrottledMs != nil {
+		parts = append(parts, "throttled_ms="+strconv.FormatInt(*rec.ThrottledMs, 10))
+	}
+	if rec.PackageWatts != nil {
+		parts = append(parts, "package_watts="+strconv.FormatFloat(*rec.PackageWatts, 'f', 1, 64))
+	}
+	if rec.UtilizationPct != nil {
+		parts = append(parts, "utilization_pct="+strconv.FormatFloat(*rec.UtilizationPct, 'f', 1, 64))
+	}
+	for _, sock := range rec.Sockets {
+		prefix := "socket" + strconv.Itoa(sock.Package) + "_"
+		if sock.OpsPerSec != nil {
+			parts = append(parts, prefix+"ops_per_sec="+strconv.FormatFloat(*sock.OpsPerSec, 'f', 0, 64))
+		}
+		if sock.CPUFreqMHz != nil {
+			parts = append(parts, prefix+"cpu_freq_mhz="+strconv.Itoa(*sock.CPUFreqMHz))
+		}
+		if sock.TemperatureC != nil {
+			parts = append(parts, prefix+"temperature_c="+float(*sock.TemperatureC))
+		}
+		if sock.PackageWatts != nil {
+			parts = append(parts, prefix+"package_watts="+strconv.FormatFloat(*sock.PackageWatts, 'f', 1, 64))
+		}
+	}
+	if rec.Warmup {
+		parts = append(parts, "warmup=true")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"goburn/burn"
+	"goburn/hardware"
+)
+
+func TestFormatSampleSchema(t *testing.T) {
+	full := burn.Sample{
+		Elapsed:      5 * time.Second,
+		Interval:     time.Second,
+		OpsPerSec:    123_456_789.4,
+		Workers:      4,
+		Throttled:    20 * time.Millisecond,
+		Utilization:  99.5,
+		PackageWatts: 180.4,
+		Sockets: []burn.SocketSample{
+			{Package: 0, OpsPerSec: 61_000_000.4, CPUFreqMHz: 2300, Temperature: 62, PackageWatts: 90.5},
+			{Package: 1, CPUFreqMHz: 2100},
+		},
+		Stats: hardware.Stats{
+			CPUFreqCur:    2200,
+			CPUFreqMax:    4400,
+			CPUFreqPct:    50,
+			Temperature:   61.5,
+			FanRPMs:       []int{1000, 2000},
+			ThrottledUSec: 20000,
+		},
+	}
+	bare := burn.Sample{Elapsed: time.Second, Interval: time.Second, OpsPerSec: 10, Workers: 1, Stats: hardware.Stats{ThrottledUSec: -1}}
+	warmup := bare
+	warmup.Warmup = true
+
+	cases := []struct {
+		format Format
+		sample burn.Sample
+		want   string
+	}{
+		{FormatJSON, full, `{"version":1,"elapsed_s":5,"interval_s":1,"duration_s":60,"workers":4,"ops_per_sec":123456789,` +
+			`"cpu_freq_mhz":2200,"cpu_freq_max_mhz":4400,"cpu_freq_pct":50,"temperature_c":61.5,` +
+			`"fan_avg_rpm":1500,"throttled_ms":20,"package_watts":180.4,"utilization_pct":99.5,` +
+			`"sockets":[{"package":0,"ops_per_sec":61000000,"cpu_freq_mhz":2300,"temperature_c":62,"package_watts":90.5},` +
+			`{"package":1,"ops_per_sec":null,"cpu_freq_mhz":2100,"temperature_c":null,"package_watts":null}]}`},
+		{FormatJSON, bare, `{"version":1,"elapsed_s":1,"interval_s":1,"duration_s":60,"workers":1,"ops_per_sec":10,` +
+			`"cpu_freq_mhz":null,"cpu_freq_max_mhz":null,"cpu_freq_pct":null,"temperature_c":null,` +
+			`"fan_avg_rpm":null,"throttled_ms":null,"package_watts":null,"utilization_pct":null,"sockets":null}`},
+		{FormatLogfmt, full, "version=1 elapsed_s=5 interval_s=1 duration_s=60 workers=4 ops_per_sec=123456789 " +
+			"cpu_freq_mhz=2200 cpu_freq_max_mhz=4400 cpu_freq_pct=50.0 temperature_c=61.5 " +
+			"fan_avg_rpm=1500 throttled_ms=20 package_watts=180.4 utilization_pct=99.5 " +
+			"socket0_ops_per_sec=61000000 socket0_cpu_freq_mhz=2300 socket0_temperature_c=62 socket0_package_watts=90.5 " +
+			"socket1_cpu_freq_mhz=2100"},
+		{FormatLogfmt, bare, "version=1 elapsed_s=1 interval_s=1 duration_s=60 workers=1 ops_per_sec=10"},
+		{FormatText, full, "[5s] ops=123M/s | cpu=2200/4400MHz (50%) | temp=61.5C | fans=1000,2000RPM" +
+			" | socket0 ops=61M/s cpu=2300MHz temp=62.0C power=90.5W | socket1 cpu=2100MHz | throttled=20ms"},
+		{FormatText, warmup, "[1s] ops=0M/s | warmup"},
+		{FormatJSON, warmup, `{"version":1,"elapsed_s":1,"interval_s":1,"duration_s":60,"workers":1,"ops_per_sec":10,` +
+			`"cpu_freq_mhz":null,"cpu_freq_max_mhz":null,"cpu_freq_pct":null,"temperature_c":null,` +
+			`"fan_avg_rpm":null,"throttled_ms":null,"package_watts":null,"utilization_pct":null,"sockets":null,"warmup":true}`},
+		{FormatLogfmt, warmup, "version=1 elapsed_s=1 interval_s=1 duration_s=60 workers=1 ops_per_sec=10 warmup=true"},
+	}
+	for _, c := range cases {
+		if got := formatSample(c.format, c.sample, time.Minute); got != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", c.format, got, c.want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range Formats {
+		if got, err := ParseFormat(string(f)); err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %q, %v", f, got, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded")
+	}
+}
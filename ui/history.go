@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"goburn/burn"
+)
+
+// bucket aggregates the values of a metric that fall in one time slot.
+type bucket struct {
+	start    time.Duration // Elapsed time at the start of the slot
+	min, max float64
+	sum      float64
+	n        int
+}
+
+// avg returns the mean of the values in the bucket.
+func (b bucket) avg() float64 {
+	if b.n == 0 {
+		return 0
+	}
+	return b.sum / float64(b.n)
+}
+
+// merge folds the values of o into b.
+func (b *bucket) merge(o bucket) {
+	b.sum += o.sum
+	b.n += o.n
+	b.min = min(b.min, o.min)
+	b.max = max(b.max, o.max)
+}
+
+// series is a list of fixed-width time buckets of one metric, oldest first.
+// Once it holds limit buckets it either drops the oldest ones, or, if
+// compact is set, halves its resolution so it keeps covering the whole run.
+type series struct {
+	width   time.Duration // Bucket width, 0 for one bucket per value
+	limit   int           // Maximum buckets kept
+	compact bool          // Merge bucket pairs instead of dropping old buckets
+	trimmed bool          // Set once old buckets have been dropped
+	buckets []bucket
+}
+
+// add records value v observed at elapsed time at.
+func (s *series) add(at time.Duration, v float64) {
+	if n := len(s.buckets); n > 0 && s.width > 0 {
+		last := &s.buckets[n-1]
+		if at < last.start+s.width {
+			last.merge(bucket{min: v, max: v, sum: v, n: 1})
+			return
+		}
+	}
+
+	start := at
+	if s.width > 0 {
+		start = at.Truncate(s.width)
+	}
+	s.buckets = append(s.buckets, bucket{start: start, min: v, max: v, sum: v, n: 1})
+	if len(s.buckets) <= s.limit {
+		return
+	}
+	if s.compact && s.width > 0 {
+		s.width *= 2
+		s.buckets = rebucket(s.buckets, s.width)
+		return
+	}
+	s.buckets = s.buckets[len(s.buckets)-s.limit:]
+	s.trimmed = true
+}
+
+// covers reports whether the series still holds every value since from.
+func (s *series) covers(from time.Duration) bool {
+	return !s.trimmed || (len(s.buckets) > 0 && s.buckets[0].start <= from)
+}
+
+// window returns the buckets starting between from and to, merged into
+// buckets of the given width when it is coarser than the series' own.
+func (s *series) window(from, to, width time.Duration) []bucket {
+	var out []bucket
+	for _, b := range s.buckets {
+		if b.start >= from && b.start <= to {
+			out = append(out, b)
+		}
+	}
+	if width <= s.width {
+		return out
+	}
+	return rebucket(out, width)
+}
+
+// rebucket merges consecutive buckets that fall in the same slot of width.
+func rebucket(buckets []bucket, width time.Duration) []bucket {
+	var out []bucket
+	for _, b := range buckets {
+		start := b.start.Truncate(width)
+		if n := len(out); n > 0 && out[n-1].start == start {
+			out[n-1].merge(b)
+			continue
+		}
+		b.start = start
+		out = append(out, b)
+	}
+	return out
+}
+
+// track keeps one metric at two resolutions: every value of the last
+// recentSpan, and a downsampled copy of the whole run.
+type track struct {
+	recent series
+	whole  series
+}
+
+// recentSpan is how far back a track keeps every value.
+const recentSpan = 10 * time.Minute
+
+// wholeLimit is the number of buckets kept for the whole run. The bucket
+// width doubles each time the run outgrows them.
+const wholeLimit = 1024
+
+// newTrack creates a track for values sampled every interval.
+func newTrack(interval time.Duration) track {
+	return track{
+		recent: series{limit: int(recentSpan/interval) + 1},
+		whole:  series{width: max(interval, time.Second), limit: wholeLimit, compact: true},
+	}
+}
+
+// add records value v observed at elapsed time at.
+func (t *track) add(at time.Duration, v float64) {
+	t.recent.add(at, v)
+	t.whole.add(at, v)
+}
+
+// window returns the buckets between from and to at the given width, from
+// the full-resolution series when it reaches back far enough.
+func (t *track) window(from, to, width time.Duration) []bucket {
+	if t.recent.covers(from) {
+		return t.recent.window(from, to, width)
+	}
+	return t.whole.window(from, to, width)
+}
+
+// history holds the graphed metrics of the whole run.
+type history struct {
+	ops  track // Operations in M/s
+	cpu  track // CPU frequency in percent of max
+	temp track // Temperature in Celsius
+	fan  track // Average fan speed in RPM
+
+	power track // RAPL package power in W
+	util  track // CPU utilization in percent
+}
+
+// newHistory creates a history for samples taken every interval.
+func newHistory(interval time.Duration) *history {
+	return &history{
+		ops:  newTrack(interval),
+		cpu:  newTrack(interval),
+		temp: newTrack(interval),
+		fan:  newTrack(interval),
+
+		power: newTrack(interval),
+		util:  newTrack(interval),
+	}
+}
+
+// add records the metrics of a sample that are available.
+func (h *history) add(s burn.Sample) {
+	h.ops.add(s.Elapsed, s.OpsPerSec/1_000_000)
+	if s.Stats.CPUFreqPct > 0 {
+		h.cpu.add(s.Elapsed, s.Stats.CPUFreqPct)
+	}
+	if s.Stats.Temperature > 0 {
+		h.temp.add(s.Elapsed, s.Stats.Temperature)
+	}
+	if len(s.Stats.FanRPMs) > 0 {
+		avgFan := 0.0
+		for _, rpm := range s.Stats.FanRPMs {
+			avgFan += float64(rpm)
+		}
+		h.fan.add(s.Elapsed, avgFan/float64(len(s.Stats.FanRPMs)))
+	}
+	if s.PackageWatts > 0 {
+		h.power.add(s.Elapsed, s.PackageWatts)
+	}
+	if s.Utilization > 0 {
+		h.util.add(s.Elapsed, s.Utilization)
+	}
+}
+
+// averages returns the mean of every bucket, oldest first.
+func averages(buckets []bucket) []float64 {
+	values := make([]float64, len(buckets))
+	for i, b := range buckets {
+		values[i] = b.avg()
+	}
+	return values
+}
+
+// aggregations are the bucket widths the TUI can graph, cycled with 'a'.
+// Zero graphs every sample as its own point. Wide zooms use coarser
+// buckets when the graph has fewer columns than points.
+var aggregations = []time.Duration{0, time.Second, 10 * time.Second, time.Minute}
+
+// defaultAggregation returns the index of the aggregation a run starts
+// with: one point per second, or per sample if samples are coarser. That
+// is the widest bucket no wider than a second or the sample interval.
+func defaultAggregation(interval time.Duration) int {
+	limit := max(interval, time.Second)
+	index := 0
+	for i, width := range aggregations {
+		if width <= limit {
+			index = i
+		}
+	}
+	return index
+}
+
+// aggregationLabel names a bucket width for the header.
+func aggregationLabel(width time.Duration) string {
+	if width == 0 {
+		return "raw"
+	}
+	return formatElapsed(width)
+}
+
+// zooms are the time spans the TUI can graph, cycled with 'z'.
+// Zero shows the whole run.
+var zooms = []time.Duration{time.Minute, recentSpan, 0}
+
+// zoomLabel names a time span for the header.
+func zoomLabel(span time.Duration) string {
+	if span == 0 {
+		return "whole run"
+	}
+	return "last " + formatElapsed(span)
+}
+
+// niceWidths are the bucket widths picked when a zoom has more points than
+// the graph has columns, so that buckets stay aligned while the run grows.
+var niceWidths = []time.Duration{
+	100 * time.Millisecond, 200 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second,
+	15 * time.Second, 30 * time.Second, time.Minute, 2 * time.Minute,
+	5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+}
+
+// niceWidth returns the smallest nice bucket width of at least d.
+func niceWidth(d time.Duration) time.Duration {
+	for _, w := range niceWidths {
+		if w >= d {
+			return w
+		}
+	}
+	return d.Truncate(time.Hour) + time.Hour
+}
+
+// graphView is the time range and resolution the graphs show.
+type graphView struct {
+	from, to time.Duration // Elapsed time range
+	width    time.Duration // Bucket width, 0 for one point per sample
+}
+
+// newGraphView returns the view of the given span ending scroll before
+// latest, with at most columns points. A zero span shows the whole run.
+// Scrolling stops at the start of the run.
+func newGraphView(latest, span, scroll, agg time.Duration, columns int) graphView {
+	v := graphView{to: latest, width: agg}
+	if span > 0 {
+		v.to = max(min(latest-scroll, latest), min(span, latest))
+		v.from = max(v.to-span, 0)
+	} else {
+		span = latest
+	}
+	if columns > 0 && span/time.Duration(columns) > v.width {
+		v.width = niceWidth(span / time.Duration(columns))
+	}
+	return v
+}
+
+// period is a range of elapsed time, such as a pause of the workers.
+// An ongoing period has a negative end.
+type period struct {
+	from, to time.Duration
+}
+
+// formatElapsed formats an elapsed time compactly, such as "90s" as "1m30s"
+// and "10m0s" as "10m".
+func formatElapsed(d time.Duration) string {
+	if d >= time.Second {
+		d = d.Round(time.Second)
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"goburn/burn"
+)
+
+func TestTrackKeepsWholeRun(t *testing.T) {
+	tr := newTrack(time.Second)
+	run := 3 * time.Hour
+	for at := time.Second; at <= run; at += time.Second {
+		tr.add(at, float64(at/time.Second))
+	}
+
+	if got := len(tr.recent.buckets); got != tr.recent.limit {
+		t.Fatalf("recent buckets = %d, want %d", got, tr.recent.limit)
+	}
+	if got := len(tr.whole.buckets); got > wholeLimit {
+		t.Fatalf("whole-run buckets = %d, want at most %d", got, wholeLimit)
+	}
+
+	// The whole run is still there, with its extremes intact
+	all := tr.window(0, run, 0)
+	if all[0].start != 0 || all[0].min != 1 {
+		t.Fatalf("first bucket = %+v, want start 0 and min 1", all[0])
+	}
+	var n int
+	var peak float64
+	for _, b := range all {
+		n += b.n
+		peak = max(peak, b.max)
+	}
+	if n != int(run/time.Second) || peak != float64(run/time.Second) {
+		t.Fatalf("whole run has %d values up to %v, want %d up to %d", n, peak, run/time.Second, run/time.Second)
+	}
+
+	// The last minute comes from the full-resolution series
+	last := tr.window(run-time.Minute, run, 0)
+	if len(last) != 61 {
+		t.Fatalf("last minute has %d buckets, want 61", len(last))
+	}
+
+	tens := tr.window(run-time.Minute, run, 10*time.Second)
+	if len(tens) != 7 || tens[0].n != 10 || tens[0].avg() != 10744.5 {
+		t.Fatalf("10s buckets = %d, first %+v", len(tens), tens[0])
+	}
+}
+
+func TestNewGraphView(t *testing.T) {
+	cases := []struct {
+		name                 string
+		latest, span, scroll time.Duration
+		agg                  time.Duration
+		columns              int
+		want                 graphView
+	}{
+		{"live", 5 * time.Minute, time.Minute, 0, 0, 60,
+			graphView{from: 4 * time.Minute, to: 5 * time.Minute, width: time.Second}},
+		{"young run", 30 * time.Second, time.Minute, 0, time.Second, 60,
+			graphView{from: 0, to: 30 * time.Second, width: time.Second}},
+		{"scrolled", 5 * time.Minute, time.Minute, 2 * time.Minute, 0, 60,
+			graphView{from: 2 * time.Minute, to: 3 * time.Minute, width: time.Second}},
+		{"scrolled past start", 5 * time.Minute, time.Minute, time.Hour, 0, 60,
+			graphView{from: 0, to: time.Minute, width: time.Second}},
+		{"whole run", time.Hour, 0, 0, 0, 50,
+			graphView{from: 0, to: time.Hour, width: 2 * time.Minute}},
+		{"coarser aggregation", time.Hour, 10 * time.Minute, 0, time.Minute, 50,
+			graphView{from: 50 * time.Minute, to: time.Hour, width: time.Minute}},
+	}
+	for _, c := range cases {
+		got := newGraphView(c.latest, c.span, c.scroll, c.agg, c.columns)
+		if got != c.want {
+			t.Errorf("%s: view = %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestDefaultAggregation(t *testing.T) {
+	cases := []struct {
+		interval time.Duration
+		want     time.Duration
+	}{
+		{100 * time.Millisecond, time.Second},
+		{500 * time.Millisecond, time.Second},
+		{time.Second, time.Second},
+		{5 * time.Second, time.Second},
+		{10 * time.Second, 10 * time.Second},
+		{2 * time.Minute, time.Minute},
+	}
+	for _, c := range cases {
+		if got := aggregations[defaultAggregation(c.interval)]; got != c.want {
+			t.Errorf("defaultAggregation(%v) = %v, want %v", c.interval, got, c.want)
+		}
+	}
+}
+
+func TestRenderTimeAxis(t *testing.T) {
+	graph := " 100 ┤" + "          " + "          " + "          " + "\n" + "   0 ┼"
+	got := renderTimeAxis(graph, 0, 10*time.Minute, nil, nil)
+	want := "      0s            5m           10m"
+	if got != want {
+		t.Fatalf("axis =\n%q\nwant\n%q", got, want)
+	}
+
+	// Pauses are shaded under the labels, clipped to the view
+	pauses := []period{{from: 2 * time.Minute, to: 3 * time.Minute}, {from: 9 * time.Minute, to: 11 * time.Minute}}
+	got = renderTimeAxis(graph, 0, 10*time.Minute, pauses, nil)
+	want = "      0s   ▒▒▒▒     5m          ▒10m"
+	if got != want {
+		t.Fatalf("axis with pauses =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDrawMarkers(t *testing.T) {
+	graph := " 100 ┤" + "\x1b[33m──────────\x1b[0m" + "\n" + "   0 ┼"
+	markers := []burn.Marker{{Elapsed: 2 * time.Second}, {Elapsed: 9 * time.Second}, {Elapsed: time.Minute}}
+	got := drawMarkers(graph, 0, 9*time.Second, markers)
+	want := " 100 ┤" + "\x1b[33m──────────\x1b[0m" + "\n" + "   0 ┼  ┊      ┊"
+	if got != want {
+		t.Fatalf("graph =\n%q\nwant\n%q", got, want)
+	}
+
+	axis := renderTimeAxis(got, 0, 9*time.Second, nil, markers)
+	if want := "      0s1     92"; axis != want {
+		t.Fatalf("axis = %q, want %q", axis, want)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	cases := map[time.Duration]string{
+		90 * time.Second:                    "1m30s",
+		10 * time.Minute:                    "10m",
+		2 * time.Hour:                       "2h",
+		time.Hour + 5*time.Minute:           "1h5m",
+		250 * time.Millisecond:              "250ms",
+		time.Minute + 1400*time.Millisecond: "1m1s",
+	}
+	for d, want := range cases {
+		if got := formatElapsed(d); got != want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -2,38 +2,112 @@
 package ui
 
 import (
+	"context"
 	"fmt"
-	"sync/atomic"
+	"os"
 	"time"
 
+	"goburn/burn"
 	"goburn/hardware"
 )
 
-// RunLineMode displays simple line-by-line output with hardware stats.
-// This is the default non-interactive mode.
-func RunLineMode(counter *uint64, duration time.Duration, startTime time.Time) {
-	var last uint64
+// RunLineMode runs a burn described by cfg, printing one line per sample
+// in the given format. This is the default non-interactive mode. Power
+// settings that make results less comparable are printed as warnings on
+// stderr before the burn. It returns the burn report once the duration
+// has elapsed or ctx is cancelled.
+func RunLineMode(ctx context.Context, cfg burn.Config, format Format) (burn.Report, error) {
+	onStart := cfg.OnStart
+	cfg.OnStart = func(header burn.Report) {
+		for _, w := range header.Power.Warnings() {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+		if onStart != nil {
+			onStart(header)
+		}
+	}
+
+	onSample := cfg.OnSample
+	cfg.OnSample = func(s burn.Sample) {
+		fmt.Println(formatSample(format, s, cfg.Duration))
+		if onSample != nil {
+			onSample(s)
+		}
+	}
 
-	for {
-		time.Sleep(time.Second)
-		c := atomic.LoadUint64(counter)
-		ops := (c - last) / 1_000_000
-		elapsed := time.Since(startTime)
+	// Marker lines would break machine-readable formats
+	onMarker := cfg.OnMarker
+	cfg.OnMarker = func(mk burn.Marker) {
+		if format == FormatText {
+			fmt.Println(formatMarker(mk))
+		}
+		if onMarker != nil {
+			onMarker(mk)
+		}
+	}
+	return burn.Run(ctx, cfg)
+}
 
-		// Get hardware stats
-		hwStats := hardware.Get()
-		hwInfo := formatHardwareStats(hwStats)
+// formatMarker renders a marker with the metrics of the sample before it.
+func formatMarker(mk burn.Marker) string {
+	return fmt.Sprintf("[%s] marker %q | ops=%dM/s%s",
+		mk.Elapsed.Round(time.Second), mk.Label,
+		uint64(mk.Sample.OpsPerSec/1_000_000),
+		formatHardwareStats(mk.Sample.Stats))
+}
 
-		fmt.Printf("[%s] ops=%dM/s%s\n",
-			elapsed.Round(time.Second),
-			ops,
-			hwInfo)
+// formatLine renders a sample as a single output line.
+func formatLine(s burn.Sample) string {
+	// Sub-second sampling shows sub-second timestamps
+	precision := time.Second
+	if s.Interval > 0 && s.Interval < 900*time.Millisecond {
+		precision = 10 * time.Millisecond
+	}
 
-		if elapsed >= duration {
-			return
+	line := fmt.Sprintf("[%s] ops=%dM/s%s",
+		s.Elapsed.Round(precision),
+		uint64(s.OpsPerSec/1_000_000),
+		formatHardwareStats(s.Stats))
+
+	line += formatSockets(s.Sockets)
+
+	// Cgroup throttling means the container, not the hardware, is the limit
+	if s.Throttled > 0 {
+		line += fmt.Sprintf(" | throttled=%dms", s.Throttled.Milliseconds())
+	}
+
+	// Warmup samples are left out of the summary
+	if s.Warmup {
+		line += " | warmup"
+	}
+	return line
+}
+
+// formatSockets renders the per-socket readings, such as
+// " | socket0 ops=120M/s cpu=3400MHz temp=61.0C power=95.2W". Ops are
+// left out while workers are unpinned, and the whole part without
+// multiple sockets.
+func formatSockets(sockets []burn.SocketSample) string {
+	var text string
+	for _, sock := range sockets {
+		var fields string
+		if sock.OpsPerSec > 0 {
+			fields += fmt.Sprintf(" ops=%dM/s", uint64(sock.OpsPerSec/1_000_000))
+		}
+		if sock.CPUFreqMHz > 0 {
+			fields += fmt.Sprintf(" cpu=%dMHz", sock.CPUFreqMHz)
+		}
+		if sock.Temperature > 0 {
+			fields += fmt.Sprintf(" temp=%.1fC", sock.Temperature)
+		}
+		if sock.PackageWatts > 0 {
+			fields += fmt.Sprintf(" power=%.1fW", sock.PackageWatts)
+		}
+		if fields != "" {
+			text += fmt.Sprintf(" | socket%d%s", sock.Package, fields)
 		}
-		last = c
 	}
+	return text
 }
 
 // formatHardwareStats converts hardware stats into a readable string.
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"goburn/burn"
+)
+
+// handleMarkerInput edits the label of a marker being dropped with 'm'.
+// Enter drops the marker, Esc cancels it.
+func (m Model) handleMarkerInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEnter:
+		label := strings.TrimSpace(string(m.markLabel))
+		if label == "" {
+			label = fmt.Sprintf("marker %d", len(m.markers)+1)
+		}
+		marker := m.recorder.Mark(time.Since(m.startTime), label)
+		m.markers = append(slices.Clip(m.markers), marker)
+		m.markLabel = nil
+
+	case tea.KeyEsc:
+		m.markLabel = nil
+
+	case tea.KeyBackspace:
+		if n := len(m.markLabel); n > 0 {
+			m.markLabel = m.markLabel[:n-1]
+		}
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.markLabel = append(slices.Clip(m.markLabel), msg.Runes...)
+	}
+	return m, nil
+}
+
+// drawMarkers draws a dotted vertical line across the plot area of an
+// asciigraph graph for every marker between from and to. Plotted lines
+// stay on top, and color escape sequences are kept as they are.
+func drawMarkers(graph string, from, to time.Duration, markers []burn.Marker) string {
+	if to <= from {
+		return graph
+	}
+	start, width := plotArea(graph)
+	var columns []int
+	for _, mk := range markers {
+		if mk.Elapsed >= from && mk.Elapsed <= to {
+			columns = append(columns, plotColumn(mk.Elapsed, from, to, start, width))
+		}
+	}
+	if len(columns) == 0 {
+		return graph
+	}
+	last := slices.Max(columns)
+
+	lines := strings.Split(graph, "\n")
+	for i, l := range lines {
+		var b strings.Builder
+		col, esc := 0, false
+		for _, r := range l {
+			switch {
+			case esc:
+				// CSI sequences end with a final byte in @-~, after '['
+				esc = r == '[' || r < '@' || r > '~'
+			case r == '\x1b':
+				esc = true
+			default:
+				if r == ' ' && slices.Contains(columns, col) {
+					r = '┊'
+				}
+				col++
+			}
+			b.WriteRune(r)
+		}
+		// Lines can stop short of the plot's right edge
+		for ; col <= last; col++ {
+			if slices.Contains(columns, col) {
+				b.WriteRune('┊')
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
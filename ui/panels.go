@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// panelDef describes a TUI panel. Most panels graph one tracked metric;
+// the others have their own render function.
+type panelDef struct {
+	title  string
+	track  func(h *history) *track // Metric graphed, nil if render is set
+	upper  func(m Model) float64   // Y-axis upper bound
+	ready  func(m Model) bool      // Data source check, nil if any value was tracked
+	render func(m Model, title string, height, width int, focused bool) string
+}
+
+// panelDefs are the panels known by name, matching panel.Names.
+var panelDefs = map[string]panelDef{
+	"ops": {
+		title: "Operations (M/s)",
+		track: func(h *history) *track { return &h.ops },
+		upper: func(m Model) float64 { return max(float64(m.maxOps)*1.2, 100) },
+		ready: func(Model) bool { return true },
+	},
+	"freq": {
+		title: "CPU Frequency (%)",
+		track: func(h *history) *track { return &h.cpu },
+		upper: func(Model) float64 { return 100 },
+	},
+	"temp": {
+		title: "Temperature (°C)",
+		track: func(h *history) *track { return &h.temp },
+		upper: func(Model) float64 { return 100 },
+	},
+	"fans": {
+		title: "Fan Speed (RPM avg)",
+		track: func(h *history) *track { return &h.fan },
+		upper: func(m Model) float64 { return max(float64(m.maxFanRPM)*1.2, 6000) },
+	},
+	"power": {
+		title: "Package Power (W)",
+		track: func(h *history) *track { return &h.power },
+		upper: func(m Model) float64 { return max(m.maxWatts*1.2, 50) },
+	},
+	"utilization": {
+		title: "CPU Utilization (%)",
+		track: func(h *history) *track { return &h.util },
+		upper: func(Model) float64 { return 100 },
+	},
+	"per-core": {
+		title:  "Per-Core Load (%)",
+		ready:  func(m Model) bool { return len(m.coreUtil) > 0 },
+		render: Model.renderCorePanel,
+	},
+	"correlation": {
+		title: "Ops vs Frequency vs Temperature",
+		ready: func(m Model) bool {
+			return len(m.history.cpu.whole.buckets) > 0 || len(m.history.temp.whole.buckets) > 0
+		},
+		render: Model.renderCorrelationPanel,
+	},
+}
+
+// visiblePanels returns the selected panels that have a data source,
+// in display order.
+func (m Model) visiblePanels() []string {
+	var visible []string
+	for _, name := range m.panels {
+		def, ok := panelDefs[name]
+		if !ok {
+			continue
+		}
+		switch {
+		case def.ready != nil && !def.ready(m):
+			continue
+		case def.ready == nil && len(def.track(m.history).whole.buckets) == 0:
+			continue
+		}
+		visible = append(visible, name)
+	}
+	return visible
+}
+
+// layout returns the panels to draw and the grid they are laid out in.
+// A maximized panel, or the correlation view, fills the whole graph area.
+func (m Model) layout() (names []string, rows, cols int) {
+	names = m.visiblePanels()
+	switch {
+	case m.correlation:
+		names = []string{"correlation"}
+	case m.maximized && m.focus < len(names):
+		names = names[m.focus : m.focus+1]
+	}
+	cols = 2
+	if len(names) <= 1 {
+		cols = 1
+	}
+	rows = max((len(names)+cols-1)/cols, 1)
+	return names, rows, cols
+}
+
+// movePanel moves the focused panel by delta places among the selected
+// panels, and keeps it focused.
+func (m Model) movePanel(delta int) Model {
+	visible := m.visiblePanels()
+	if m.focus >= len(visible) || m.focus+delta < 0 || m.focus+delta >= len(visible) {
+		return m
+	}
+	from := slices.Index(m.panels, visible[m.focus])
+	to := slices.Index(m.panels, visible[m.focus+delta])
+	m.panels = slices.Clone(m.panels)
+	m.panels[from], m.panels[to] = m.panels[to], m.panels[from]
+	m.focus += delta
+	return m
+}
+
+// hidePanel removes the focused panel from the selection.
+// The last visible panel cannot be hidden.
+func (m Model) hidePanel() Model {
+	visible := m.visiblePanels()
+	if len(visible) <= 1 || m.focus >= len(visible) {
+		return m
+	}
+	m.panels = slices.DeleteFunc(slices.Clone(m.panels), func(name string) bool {
+		return name == visible[m.focus]
+	})
+	m.maximized = false
+	m.focus = min(m.focus, len(visible)-2)
+	return m
+}
+
+// togglePanel shows and focuses the named panel after the others, or
+// hides it if it is shown.
+func (m Model) togglePanel(name string) Model {
+	if !slices.Contains(m.panels, name) {
+		m.panels = append(slices.Clone(m.panels), name)
+		if i := slices.Index(m.visiblePanels(), name); i >= 0 {
+			m.focus = i
+		}
+		return m
+	}
+	m.panels = slices.DeleteFunc(slices.Clone(m.panels), func(p string) bool {
+		return p == name
+	})
+	m.maximized = false
+	m.focus = min(m.focus, max(len(m.visiblePanels())-1, 0))
+	return m
+}
+
+// renderCorePanel creates the per-core panel: one load bar per CPU, with
+// its frequency when cpufreq is available. With pinned workers, CPUs that
+// have a worker are marked ● and idle siblings stand out next to them.
+func (m Model) renderCorePanel(title string, height, width int, focused bool) string {
+	borderColor := m.theme.Secondary
+	panelStyle := m.panelStyle(borderColor, height, width, focused)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(borderColor).
+		Background(m.theme.Background).
+		Padding(0, 1)
+
+	pinned := make(map[int]bool, len(m.pinned))
+	for _, cpu := range m.pinned {
+		pinned[cpu] = true
+	}
+	if len(pinned) > 0 {
+		title += " · ● pinned"
+	}
+
+	const barWidth = 10
+	entryWidth := 5 + barWidth + 5
+	if len(m.coreFreqs) == len(m.coreUtil) {
+		entryWidth += 8
+	}
+	cols := max((width+8)/(entryWidth+2), 1)
+	rows := height + 4 // Content lines below the title
+
+	entries := make([]string, 0, len(m.coreUtil))
+	for i, util := range m.coreUtil {
+		if len(entries) == cols*rows-1 && len(m.coreUtil) > cols*rows {
+			entries = append(entries, fmt.Sprintf("+%d more", len(m.coreUtil)-i))
+			break
+		}
+		filled := int(util / 100 * barWidth)
+		filled = max(min(filled, barWidth), 0)
+		barStyle := lipgloss.NewStyle().Foreground(m.theme.percentColor(util))
+		emptyStyle := lipgloss.NewStyle().Foreground(m.theme.Faint)
+		mark := " "
+		if pinned[m.coreIDs[i]] {
+			mark = "●"
+		}
+		entry := fmt.Sprintf("%3d%s %s%s %3.0f%%", m.coreIDs[i], mark,
+			barStyle.Render(strings.Repeat("█", filled)),
+			emptyStyle.Render(strings.Repeat("░", barWidth-filled)),
+			util)
+		if len(m.coreFreqs) == len(m.coreUtil) {
+			entry += fmt.Sprintf(" %4dMHz", m.coreFreqs[i])
+		}
+		entries = append(entries, entry)
+	}
+
+	// Fill columns top to bottom
+	lines := make([]string, min(len(entries), rows))
+	for i, entry := range entries {
+		row := i % rows
+		if lines[row] != "" {
+			lines[row] += "  "
+		}
+		lines[row] += lipgloss.NewStyle().Width(entryWidth).Render(entry)
+	}
+
+	return panelStyle.Render(labelStyle.Render(title) + "\n\n" + strings.Join(lines, "\n"))
+}
+
+// panelStyle returns the frame of a graph panel. The focused panel has a
+// thick border.
+func (m Model) panelStyle(borderColor lipgloss.TerminalColor, height, width int, focused bool) lipgloss.Style {
+	border := lipgloss.DoubleBorder()
+	if focused {
+		border = lipgloss.ThickBorder()
+	}
+	// Fixed size for consistent panel alignment
+	return lipgloss.NewStyle().
+		Border(border).
+		BorderForeground(borderColor).
+		Padding(1, 1).
+		Width(width + 10).
+		Height(height + 8)
+}
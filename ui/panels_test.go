@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"goburn/burn"
+	"goburn/hardware"
+	"goburn/panel"
+)
+
+func TestPanelDefsMatchNames(t *testing.T) {
+	for _, name := range panel.Names {
+		if _, ok := panelDefs[name]; !ok {
+			t.Errorf("panel %q has no definition", name)
+		}
+	}
+	if len(panelDefs) != len(panel.Names) {
+		t.Errorf("%d panel definitions for %d panel names", len(panelDefs), len(panel.Names))
+	}
+	for _, name := range panel.Defaults {
+		if !slices.Contains(panel.Names, name) {
+			t.Errorf("default panel %q is not a panel name", name)
+		}
+	}
+}
+
+func TestThemesSetEveryRole(t *testing.T) {
+	for name, theme := range themes {
+		v := reflect.ValueOf(theme)
+		for i := range v.NumField() {
+			if f := v.Field(i); f.Kind() == reflect.Interface && f.IsNil() {
+				t.Errorf("theme %q has no %s color", name, v.Type().Field(i).Name)
+			}
+		}
+	}
+}
+
+func TestAutoTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if theme, _ := LookupTheme("auto"); theme.Name != "monochrome" {
+		t.Errorf("auto theme with NO_COLOR = %q, want monochrome", theme.Name)
+	}
+	if theme, _ := LookupTheme("light"); theme.Name != "light" {
+		t.Errorf("explicit theme with NO_COLOR = %q, want light", theme.Name)
+	}
+	t.Setenv("NO_COLOR", "")
+	if theme, _ := LookupTheme(""); theme.Name != "dark" {
+		t.Errorf("default theme = %q, want dark", theme.Name)
+	}
+}
+
+func TestVisiblePanels(t *testing.T) {
+	selected := []string{"ops", "freq", "temp", "fans", "power", "utilization", "per-core"}
+	m := Model{history: newHistory(time.Second), panels: selected}
+	if got := m.visiblePanels(); !slices.Equal(got, []string{"ops"}) {
+		t.Fatalf("before any sample: %v, want [ops]", got)
+	}
+
+	// A machine with temperature and CPU time but no cpufreq, fans or RAPL
+	sample := burn.Sample{
+		Elapsed:     time.Second,
+		OpsPerSec:   1e6,
+		Utilization: 99,
+		Stats:       hardware.Stats{Temperature: 60},
+	}
+	m.history.add(sample)
+	m.coreUtil = []float64{99}
+	if got, want := m.visiblePanels(), []string{"ops", "temp", "utilization", "per-core"}; !slices.Equal(got, want) {
+		t.Fatalf("visible = %v, want %v", got, want)
+	}
+
+	// Moving and hiding act on the focused visible panel
+	m.focus = 1
+	m = m.movePanel(1)
+	if got, want := m.visiblePanels(), []string{"ops", "utilization", "temp", "per-core"}; !slices.Equal(got, want) || m.focus != 2 {
+		t.Fatalf("after move: %v focus %d, want %v focus 2", got, m.focus, want)
+	}
+	m = m.hidePanel()
+	if got, want := m.visiblePanels(), []string{"ops", "utilization", "per-core"}; !slices.Equal(got, want) {
+		t.Fatalf("after hide: %v, want %v", got, want)
+	}
+	m = m.togglePanel("per-core")
+	if got, want := m.visiblePanels(), []string{"ops", "utilization"}; !slices.Equal(got, want) {
+		t.Fatalf("after toggling per-core off: %v, want %v", got, want)
+	}
+	m = m.togglePanel("per-core")
+	if got, want := m.visiblePanels(), []string{"ops", "utilization", "per-core"}; !slices.Equal(got, want) {
+		t.Fatalf("after toggling per-core on: %v, want %v", got, want)
+	}
+	if len(selected) != 7 || selected[2] != "temp" {
+		t.Fatalf("moving, hiding and toggling panels changed the configured list: %v", selected)
+	}
+
+	m.maximized = true
+	if names, rows, cols := m.layout(); !slices.Equal(names, []string{"per-core"}) || rows != 1 || cols != 1 {
+		t.Fatalf("maximized layout = %v %dx%d", names, rows, cols)
+	}
+	m.correlation = true
+	if names, _, _ := m.layout(); !slices.Equal(names, []string{"correlation"}) {
+		t.Fatalf("correlation layout = %v", names)
+	}
+}
+
+func TestFormatBinding(t *testing.T) {
+	for _, tc := range []struct {
+		pinned []int
+		want   string
+	}{
+		{nil, "spread placement, no workers pinned"},
+		{[]int{0, 2, 1}, "spread placement, workers on CPUs 0-2"},
+		{[]int{0, 1, 0, 1, 0}, "spread placement, workers on CPUs 0-1 (3 extra workers share CPUs)"},
+	} {
+		if got := formatBinding(burn.Binding{Placement: hardware.PlaceSpread}, tc.pinned); got != tc.want {
+			t.Errorf("formatBinding(%v) = %q, want %q", tc.pinned, got, tc.want)
+		}
+	}
+}
+
+func TestCorrelationData(t *testing.T) {
+	h := newHistory(time.Second)
+	for _, s := range []burn.Sample{
+		{Elapsed: time.Second, OpsPerSec: 4e6, Stats: hardware.Stats{CPUFreqPct: 100, Temperature: 50}},
+		{Elapsed: 2 * time.Second, OpsPerSec: 2e6, Stats: hardware.Stats{Temperature: 90}},
+	} {
+		h.add(s)
+	}
+
+	series := correlationData(h, graphView{from: 0, to: 2 * time.Second}, themes["dark"].Series)
+	if len(series) != 3 {
+		t.Fatalf("got %d series, want ops, freq and temp", len(series))
+	}
+	ops, freq, temp := series[0].values, series[1].values, series[2].values
+	if ops[0] != 100 || ops[1] != 50 {
+		t.Errorf("ops = %v, want [100 50]", ops)
+	}
+	if freq[0] != 100 || !math.IsNaN(freq[1]) {
+		t.Errorf("freq = %v, want [100 NaN]", freq)
+	}
+	if temp[0] != 40 || temp[1] != 72 {
+		t.Errorf("temp = %v, want [40 72] (percent of 125°C)", temp)
+	}
+}
+
+func TestTakeTimeKeepsWarmup(t *testing.T) {
+	m := Model{
+		startTime: time.Now(),
+		duration:  90 * time.Second,
+		warmup:    45 * time.Second,
+		interval:  time.Second,
+	}
+	next, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if got, want := next.(Model).duration, 46*time.Second; got != want {
+		t.Errorf("duration after T = %v, want %v", got, want)
+	}
+}
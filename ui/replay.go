@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"goburn/burn"
+)
+
+// RunReplayMode prints the samples of a recorded report as line mode
+// would in the given format. With speed > 0 the lines are paced at speed
+// times real time; with speed 0 they are printed at once. The text format
+// adds the machine, the markers where they happened, and a summary that
+// lists the markers again. It returns early if ctx is cancelled.
+func RunReplayMode(ctx context.Context, report burn.Report, speed float64, format Format) error {
+	if format == FormatText {
+		if machine := report.Inventory.Short(); machine != "" {
+			fmt.Printf("machine: %s\n", machine)
+		}
+		if power := report.Power.String(); power != "" {
+			fmt.Printf("power: %s\n", power)
+		}
+	}
+
+	var last time.Duration
+	markers := report.Markers
+	for _, s := range report.Samples {
+		if speed > 0 {
+			wait := time.Duration(float64(s.Elapsed-last) / speed)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		last = s.Elapsed
+		for format == FormatText && len(markers) > 0 && markers[0].Elapsed <= s.Elapsed {
+			fmt.Println(formatMarker(markers[0]))
+			markers = markers[1:]
+		}
+		fmt.Println(formatSample(format, s, report.Duration))
+	}
+
+	if format == FormatText {
+		printSummary(report)
+	}
+	return nil
+}
+
+// printSummary prints the summary line of a report, followed by its
+// markers.
+func printSummary(report burn.Report) {
+	fmt.Println(formatSummary(report))
+	for _, mk := range report.Markers {
+		fmt.Println(formatMarker(mk))
+	}
+}
+
+// formatSummary renders the report summary as a single line.
+func formatSummary(report burn.Report) string {
+	sum := report.Summary
+	workers := fmt.Sprintf("%d workers", report.Workers)
+	if report.Binding.Pinned() {
+		workers += " pinned by " + report.Binding.String()
+	}
+	line := fmt.Sprintf("summary: %s, %s | ops avg=%.0fM/s min=%.0fM/s max=%.0fM/s",
+		report.End.Sub(report.Start).Round(time.Second), workers,
+		sum.OpsPerSec.Avg/1_000_000, sum.OpsPerSec.Min/1_000_000, sum.OpsPerSec.Max/1_000_000)
+	if sum.CPUFreqMHz.Count > 0 {
+		line += fmt.Sprintf(" | cpu avg=%.0fMHz", sum.CPUFreqMHz.Avg)
+	}
+	if sum.Temperature.Count > 0 {
+		line += fmt.Sprintf(" | temp avg=%.1fC max=%.1fC", sum.Temperature.Avg, sum.Temperature.Max)
+	}
+	if sum.FanRPM.Count > 0 {
+		line += fmt.Sprintf(" | fans avg=%.0fRPM", sum.FanRPM.Avg)
+	}
+	if sum.Power.Count > 0 {
+		line += fmt.Sprintf(" | power avg=%.1fW", sum.Power.Avg)
+	}
+	for _, sock := range sum.Sockets {
+		var fields string
+		if sock.OpsPerSec.Count > 0 {
+			fields += fmt.Sprintf(" ops avg=%.0fM/s", sock.OpsPerSec.Avg/1_000_000)
+		}
+		if sock.CPUFreqMHz.Count > 0 {
+			fields += fmt.Sprintf(" cpu avg=%.0fMHz", sock.CPUFreqMHz.Avg)
+		}
+		if sock.Temperature.Count > 0 {
+			fields += fmt.Sprintf(" temp max=%.1fC", sock.Temperature.Max)
+		}
+		if sock.Power.Count > 0 {
+			fields += fmt.Sprintf(" power avg=%.1fW", sock.Power.Avg)
+		}
+		if fields != "" {
+			line += fmt.Sprintf(" | socket%d%s", sock.Package, fields)
+		}
+	}
+	if sum.Throttled > 0 {
+		line += fmt.Sprintf(" | throttled=%s", sum.Throttled.Round(time.Millisecond))
+	}
+	if report.Steady {
+		line += fmt.Sprintf(" | steady after %s", report.SteadyAt.Round(time.Second))
+	}
+	if report.Warmup > 0 {
+		line += fmt.Sprintf(" | warmup=%s excluded", report.Warmup)
+	}
+	if sum.Paused > 0 {
+		line += fmt.Sprintf(" | paused=%s", sum.Paused.Round(time.Second))
+	}
+	return line
+}
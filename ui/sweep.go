@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"goburn/burn"
+)
+
+// RunSweepMode runs a scaling sweep described by cfg, printing a line as
+// each step completes and the efficiency table at the end. Power settings
+// warnings are printed on stderr before the sweep.
+func RunSweepMode(ctx context.Context, cfg burn.SweepConfig) (burn.SweepResult, error) {
+	onStart := cfg.OnStart
+	cfg.OnStart = func(header burn.Report) {
+		for _, w := range header.Power.Warnings() {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+		if onStart != nil {
+			onStart(header)
+		}
+	}
+
+	done := 0
+	onStep := cfg.OnStep
+	cfg.OnStep = func(s burn.SweepStep) {
+		done++
+		fmt.Printf("[%d/%d] workers=%d ops=%dM/s efficiency=%.1f%%\n",
+			done, len(cfg.Steps), s.Workers, uint64(s.Summary.OpsPerSec.Avg/1_000_000), s.Efficiency)
+		if onStep != nil {
+			onStep(s)
+		}
+	}
+
+	result, err := burn.RunSweep(ctx, cfg)
+	if len(result.Steps) > 0 {
+		fmt.Println()
+		printSweepTable(result.Steps)
+	}
+	return result, err
+}
+
+// printSweepTable prints one row per step. Columns of metrics the machine
+// does not have are left out.
+func printSweepTable(steps []burn.SweepStep) {
+	var hasTemp, hasFreq, hasPower bool
+	for _, s := range steps {
+		hasTemp = hasTemp || s.Summary.Temperature.Count > 0
+		hasFreq = hasFreq || s.Summary.CPUFreqMHz.Count > 0
+		hasPower = hasPower || s.Summary.Power.Count > 0
+	}
+
+	header := []string{"WORKERS", "OPS M/s", "PER WORKER", "SPEEDUP", "EFFICIENCY"}
+	if hasTemp {
+		header = append(header, "TEMP °C")
+	}
+	if hasFreq {
+		header = append(header, "FREQ MHz")
+	}
+	if hasPower {
+		header = append(header, "POWER W", "M OPS/J")
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, s := range steps {
+		sum := s.Summary
+		cells := []string{
+			fmt.Sprint(s.Workers),
+			fmt.Sprintf("%.1f", sum.OpsPerSec.Avg/1_000_000),
+			fmt.Sprintf("%.1f", s.OpsPerWorker/1_000_000),
+			fmt.Sprintf("%.2f×", s.Speedup),
+			fmt.Sprintf("%.1f%%", s.Efficiency),
+		}
+		if hasTemp {
+			cells = append(cells, formatStat(sum.Temperature, "%.1f"))
+		}
+		if hasFreq {
+			cells = append(cells, formatStat(sum.CPUFreqMHz, "%.0f"))
+		}
+		if hasPower {
+			perJoule := "-"
+			if sum.Power.Avg > 0 {
+				perJoule = fmt.Sprintf("%.2f", sum.OpsPerSec.Avg/sum.Power.Avg/1_000_000)
+			}
+			cells = append(cells, formatStat(sum.Power, "%.1f"), perJoule)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+}
+
+// formatStat formats the average of stat, or "-" if it has no samples.
+func formatStat(stat burn.Stat, format string) string {
+	if stat.Count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf(format, stat.Avg)
+}
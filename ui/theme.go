@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+)
+
+// Theme is the set of colors the TUI draws with. Every color has a role,
+// so a theme only has to keep each role readable against its background.
+type Theme struct {
+	Name string
+
+	Accent     lipgloss.TerminalColor // Title, header border and key hints
+	Background lipgloss.TerminalColor // Behind labels and the title
+	Text       lipgloss.TerminalColor // Emphasized values
+	Muted      lipgloss.TerminalColor // Labels, axes and secondary text
+	Faint      lipgloss.TerminalColor // Dividers, frames and empty bars
+	Info       lipgloss.TerminalColor // Clock and CPU frequency panel
+	Secondary  lipgloss.TerminalColor // Workers, fans, utilization and per-core panels
+	Fans       lipgloss.TerminalColor // Fan speed card
+
+	// Severity scale, from nominal to critical
+	Good     lipgloss.TerminalColor
+	Warn     lipgloss.TerminalColor
+	Hot      lipgloss.TerminalColor
+	Critical lipgloss.TerminalColor
+
+	// Graph lines of the metric panels
+	Ops       lipgloss.TerminalColor
+	FreqLine  lipgloss.TerminalColor
+	FanLine   lipgloss.TerminalColor
+	PowerLine lipgloss.TerminalColor
+
+	Series [3]asciigraph.AnsiColor // Correlation ops, frequency and temperature lines
+}
+
+// themes are the built-in themes, matching config.ThemeNames.
+var themes = map[string]Theme{
+	"dark": {
+		Name:       "dark",
+		Accent:     lipgloss.Color("#FF6B35"),
+		Background: lipgloss.Color("#1a1a1a"),
+		Text:       lipgloss.Color("#FFFFFF"),
+		Muted:      lipgloss.Color("#888888"),
+		Faint:      lipgloss.Color("#444444"),
+		Info:       lipgloss.Color("#7EC8E3"),
+		Secondary:  lipgloss.Color("#98D8C8"),
+		Fans:       lipgloss.Color("#00CED1"),
+		Good:       lipgloss.Color("#00FF87"),
+		Warn:       lipgloss.Color("#FFD700"),
+		Hot:        lipgloss.Color("#FF8C00"),
+		Critical:   lipgloss.Color("#FF0000"),
+		Ops:        lipgloss.Color("#FFD700"),
+		FreqLine:   lipgloss.Color("#00CED1"),
+		FanLine:    lipgloss.Color("#5FD7FF"),
+		PowerLine:  lipgloss.Color("#FFA500"),
+		Series:     [3]asciigraph.AnsiColor{asciigraph.Gold, asciigraph.DarkTurquoise, asciigraph.OrangeRed},
+	},
+	"light": {
+		Name:       "light",
+		Accent:     lipgloss.Color("#C2410C"),
+		Background: lipgloss.NoColor{},
+		Text:       lipgloss.Color("#000000"),
+		Muted:      lipgloss.Color("#555555"),
+		Faint:      lipgloss.Color("#AAAAAA"),
+		Info:       lipgloss.Color("#0369A1"),
+		Secondary:  lipgloss.Color("#0F766E"),
+		Fans:       lipgloss.Color("#0E7490"),
+		Good:       lipgloss.Color("#15803D"),
+		Warn:       lipgloss.Color("#A16207"),
+		Hot:        lipgloss.Color("#C2410C"),
+		Critical:   lipgloss.Color("#B91C1C"),
+		Ops:        lipgloss.Color("#A16207"),
+		FreqLine:   lipgloss.Color("#0E7490"),
+		FanLine:    lipgloss.Color("#1D4ED8"),
+		PowerLine:  lipgloss.Color("#B45309"),
+		Series:     [3]asciigraph.AnsiColor{asciigraph.DarkGoldenrod, asciigraph.Teal, asciigraph.Red},
+	},
+	"high-contrast": {
+		Name:       "high-contrast",
+		Accent:     lipgloss.Color("#FFFF00"),
+		Background: lipgloss.Color("#000000"),
+		Text:       lipgloss.Color("#FFFFFF"),
+		Muted:      lipgloss.Color("#FFFFFF"),
+		Faint:      lipgloss.Color("#BBBBBB"),
+		Info:       lipgloss.Color("#00FFFF"),
+		Secondary:  lipgloss.Color("#FFFFFF"),
+		Fans:       lipgloss.Color("#00FFFF"),
+		Good:       lipgloss.Color("#00FF00"),
+		Warn:       lipgloss.Color("#FFFF00"),
+		Hot:        lipgloss.Color("#FF8000"),
+		Critical:   lipgloss.Color("#FF0000"),
+		Ops:        lipgloss.Color("#FFFF00"),
+		FreqLine:   lipgloss.Color("#00FFFF"),
+		FanLine:    lipgloss.Color("#FFFFFF"),
+		PowerLine:  lipgloss.Color("#FF8000"),
+		Series:     [3]asciigraph.AnsiColor{asciigraph.Yellow, asciigraph.Aqua, asciigraph.Red},
+	},
+	"monochrome": {
+		Name:       "monochrome",
+		Accent:     lipgloss.NoColor{},
+		Background: lipgloss.NoColor{},
+		Text:       lipgloss.NoColor{},
+		Muted:      lipgloss.NoColor{},
+		Faint:      lipgloss.NoColor{},
+		Info:       lipgloss.NoColor{},
+		Secondary:  lipgloss.NoColor{},
+		Fans:       lipgloss.NoColor{},
+		Good:       lipgloss.NoColor{},
+		Warn:       lipgloss.NoColor{},
+		Hot:        lipgloss.NoColor{},
+		Critical:   lipgloss.NoColor{},
+		Ops:        lipgloss.NoColor{},
+		FreqLine:   lipgloss.NoColor{},
+		FanLine:    lipgloss.NoColor{},
+		PowerLine:  lipgloss.NoColor{},
+		Series:     [3]asciigraph.AnsiColor{asciigraph.Default, asciigraph.Default, asciigraph.Default},
+	},
+}
+
+// LookupTheme returns the named theme. "auto", or an empty name, picks
+// monochrome when the NO_COLOR environment variable is set and dark
+// otherwise.
+func LookupTheme(name string) (Theme, bool) {
+	if name == "" || name == "auto" {
+		name = "dark"
+		if os.Getenv("NO_COLOR") != "" {
+			name = "monochrome"
+		}
+	}
+	t, ok := themes[name]
+	return t, ok
+}
+
+// percentColor returns the color of a percentage, such as CPU frequency.
+func (t Theme) percentColor(pct float64) lipgloss.TerminalColor {
+	if pct < 50 {
+		return t.Good
+	} else if pct < 75 {
+		return t.Warn
+	}
+	return t.Accent
+}
+
+// tempColor returns the color of a temperature's severity.
+func (t Theme) tempColor(temp float64) lipgloss.TerminalColor {
+	return [...]lipgloss.TerminalColor{t.Good, t.Warn, t.Hot, t.Critical}[tempSeverity(temp)]
+}
+
+// Temperature severities, in increasing order.
+const (
+	tempCool = iota
+	tempWarm
+	tempHot
+	tempCritical
+)
+
+// tempSeverity classifies a temperature in Celsius.
+func tempSeverity(temp float64) int {
+	switch {
+	case temp < 50:
+		return tempCool
+	case temp < 70:
+		return tempWarm
+	case temp < 85:
+		return tempHot
+	}
+	return tempCritical
+}
+
+// tempLabel describes a temperature's severity with a symbol and a word,
+// so that it does not rely on color alone.
+func tempLabel(temp float64) string {
+	return [...]string{"✓ cool", "• warm", "▲ hot", "‼ critical"}[tempSeverity(temp)]
+}
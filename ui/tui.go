@@ -1,51 +1,106 @@
 package ui
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"slices"
+	"strconv"
 	"strings"
-	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/guptarohit/asciigraph"
 
+	"goburn/burn"
 	"goburn/hardware"
+	"goburn/panel"
 	"goburn/worker"
 )
 
 // Model represents the TUI application state.
 type Model struct {
-	workerPool   *worker.Pool
-	lastCounter  uint64
-	startTime    time.Time
-	duration     time.Duration
-	opsHistory   []float64
-	cpuHistory   []float64
-	tempHistory  []float64
-	fanHistory   []float64
-	maxPoints    int
-	currentStats hardware.Stats
-	currentOps   uint64
-	maxOps       uint64
-	maxFanRPM    int
-	width        int
-	height       int
+	workerPool       *worker.Pool
+	sampler          *burn.Sampler
+	recorder         *burn.Recorder
+	workerEvents     <-chan worker.Event
+	lastEvent        worker.Event // Most recent worker start/stop
+	startTime        time.Time
+	duration         time.Duration // Run length, 0 if unlimited
+	warmup           time.Duration // Initial part left out of the summary
+	steady           *burn.SteadyTracker
+	pauses           []period      // When the workers were paused
+	markers          []burn.Marker // Labelled points in time, oldest first
+	markLabel        []rune        // Label being typed, nil when not marking
+	interval         time.Duration // Sample interval
+	history          *history      // Graphed metrics of the whole run
+	latest           time.Duration // Elapsed time of the last sample
+	aggIndex         int           // Selected aggregation
+	zoomIndex        int           // Selected zoom
+	scroll           time.Duration // How far the graphs are scrolled back
+	panels           []string      // Selected panels in display order
+	focus            int           // Focused panel among the visible ones
+	maximized        bool          // Show only the focused panel
+	correlation      bool          // Show only the correlation panel
+	theme            Theme
+	configuredPanels []string // Panels selected by -panels
+	currentStats     hardware.Stats
+	sockets          []burn.SocketSample // Last per-socket readings, nil on single-socket machines
+	cgroupLimits     hardware.CgroupLimits
+	inventory        hardware.Inventory
+	power            hardware.PowerSettings
+	throttledMs      int64 // Cgroup throttled time during the last tick
+	currentOps       uint64
+	maxOps           uint64
+	maxFanRPM        int
+	maxWatts         float64
+	coreIDs          []int     // CPU numbers of the per-core readings
+	coreUtil         []float64 // Last utilization of each CPU
+	coreFreqs        []int     // Last frequency of each CPU, nil without cpufreq
+	binding          burn.Binding
+	pinned           []int // CPU of every pinned worker, nil if unpinned
+	width            int
+	height           int
 }
 
 type tickMsg time.Time
 
+type sampleMsg time.Time
+
+type workerEventMsg worker.Event
+
 // tickCmd returns a command that sends a tick message every second.
+// Ticks refresh the clock and progress bar independently of sampling.
 func tickCmd() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
-// Init initializes the TUI model and starts the tick loop.
+// sampleCmd returns a command that sends a sample message after interval.
+func sampleCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return sampleMsg(t)
+	})
+}
+
+// Init initializes the TUI model and starts the tick and sample loops.
 func (m Model) Init() tea.Cmd {
-	return tickCmd()
+	return tea.Batch(tickCmd(), sampleCmd(m.interval), waitForWorkerEvent(m.workerEvents))
+}
+
+// waitForWorkerEvent returns a command that delivers the next worker event.
+func waitForWorkerEvent(events <-chan worker.Event) tea.Cmd {
+	if events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ev, ok := <-events
+		if !ok {
+			return nil
+		}
+		return workerEventMsg(ev)
+	}
 }
 
 // Update handles incoming messages and updates the model state.
@@ -57,10 +112,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.markLabel != nil {
+			return m.handleMarkerInput(msg)
+		}
 		return m.handleKeyPress(msg)
 
 	case tickMsg:
 		return m.handleTick()
+
+	case sampleMsg:
+		return m.handleSample()
+
+	case workerEventMsg:
+		ev := worker.Event(msg)
+		switch ev.Type {
+		case worker.Paused:
+			m.pauses = append(slices.Clip(m.pauses), period{from: ev.Time.Sub(m.startTime), to: -1})
+		case worker.Resumed:
+			if n := len(m.pauses); n > 0 && m.pauses[n-1].to < 0 {
+				m.pauses = slices.Clone(m.pauses)
+				m.pauses[n-1].to = ev.Time.Sub(m.startTime)
+			}
+		default:
+			m.lastEvent = ev
+		}
+		return m, waitForWorkerEvent(m.workerEvents)
 	}
 
 	return m, nil
@@ -74,25 +150,127 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "+", "=":
 		// Increase workers
-		m.workerPool.SetWorkers(m.workerPool.GetActiveCount() + 1)
+		m.workerPool.AddWorkers(1)
 
 	case "-", "_":
 		// Decrease workers (minimum 1)
-		newCount := m.workerPool.GetActiveCount() - 1
-		if newCount >= 1 {
-			m.workerPool.SetWorkers(newCount)
+		m.workerPool.RemoveWorkers(1, 1)
+
+	case "m":
+		// Start typing a marker label
+		m.markLabel = []rune{}
+
+	case "p":
+		// Pause or resume every worker
+		if !m.workerPool.Pause() {
+			m.workerPool.Resume()
 		}
+
+	case "t":
+		// Add time, or limit an unlimited run again
+		if m.duration == 0 {
+			m.duration = time.Since(m.startTime).Round(time.Second)
+		}
+		m.duration += durationStep
+
+	case "T":
+		// Take time off; going past the current time ends the run, but
+		// never before one sample after the warmup
+		if m.duration > 0 {
+			m.duration = max(m.duration-durationStep, time.Since(m.startTime), m.warmup+m.interval)
+		}
+
+	case "u":
+		// Run until quit
+		m.duration = 0
+
+	case "a":
+		// Cycle graph aggregation
+		m.aggIndex = (m.aggIndex + 1) % len(aggregations)
+
+	case "z":
+		// Cycle zoom, back to live
+		m.zoomIndex = (m.zoomIndex + 1) % len(zooms)
+		m.scroll = 0
+
+	case "left", "[":
+		// Scroll back by half the zoomed span
+		if span := zooms[m.zoomIndex]; span > 0 {
+			m.scroll = min(m.scroll+span/2, max(m.latest-span, 0))
+		}
+
+	case "right", "]":
+		// Scroll forward, towards live
+		m.scroll = max(m.scroll-zooms[m.zoomIndex]/2, 0)
+
+	case "end":
+		// Back to live
+		m.scroll = 0
+
+	case "tab":
+		// Focus the next panel
+		m.focus = (m.focus + 1) % max(len(m.visiblePanels()), 1)
+
+	case "shift+tab":
+		// Focus the previous panel
+		n := max(len(m.visiblePanels()), 1)
+		m.focus = (m.focus + n - 1) % n
+
+	case "enter", "f":
+		// Maximize the focused panel, or go back to the grid
+		m.maximized = !m.maximized
+
+	case "<", ",":
+		// Move the focused panel earlier
+		m = m.movePanel(-1)
+
+	case ">", ".":
+		// Move the focused panel later
+		m = m.movePanel(1)
+
+	case "x":
+		// Hide the focused panel
+		m = m.hidePanel()
+
+	case "k":
+		// Show or hide the per-core panel
+		m = m.togglePanel("per-core")
+
+	case "c":
+		// Toggle the full-screen correlation view
+		m.correlation = !m.correlation
+
+	case "r":
+		// Restore the configured panels
+		m.panels = m.configuredPanels
+		m.maximized = false
 	}
 
 	return m, nil
 }
 
-// handleTick updates metrics and checks duration.
+// durationStep is how much time 't' and 'T' add to or take off the run.
+const durationStep = time.Minute
+
+// handleTick refreshes the clock and checks duration.
 func (m Model) handleTick() (tea.Model, tea.Cmd) {
-	// Update operation counter
-	c := atomic.LoadUint64(m.workerPool.GetCounter())
-	m.currentOps = (c - m.lastCounter) / 1_000_000
-	m.lastCounter = c
+	elapsed := time.Since(m.startTime)
+	if m.duration > 0 && elapsed >= m.duration {
+		return m, tea.Quit
+	}
+	if m.steady.Done(elapsed) {
+		return m, tea.Quit
+	}
+	return m, tickCmd()
+}
+
+// handleSample records a sample and updates metrics.
+func (m Model) handleSample() (tea.Model, tea.Cmd) {
+	// Sample operation counter and hardware stats
+	sample := m.sampler.Sample()
+	m.recorder.Add(sample)
+	m.currentOps = uint64(sample.OpsPerSec / 1_000_000)
+	m.latest = sample.Elapsed
 
 	// Track maximum ops for Y-axis scaling
 	if m.currentOps > m.maxOps {
@@ -100,63 +278,43 @@ func (m Model) handleTick() (tea.Model, tea.Cmd) {
 	}
 
 	// Update hardware stats
-	m.currentStats = hardware.Get()
+	m.currentStats = sample.Stats
+	m.sockets = sample.Sockets
+	m.throttledMs = sample.Throttled.Milliseconds()
 
-	// Track maximum fan RPM for Y-axis scaling
+	// Track maximum fan RPM and power for Y-axis scaling
 	for _, rpm := range m.currentStats.FanRPMs {
 		if rpm > m.maxFanRPM {
 			m.maxFanRPM = rpm
 		}
 	}
-
-	// Update history buffers
-	m.updateHistory()
-
-	// Check if duration exceeded
-	if time.Since(m.startTime) >= m.duration {
-		return m, tea.Quit
-	}
-
-	return m, tickCmd()
-}
-
-// updateHistory adds current metrics to history buffers.
-func (m *Model) updateHistory() {
-	// Operations history
-	m.opsHistory = append(m.opsHistory, float64(m.currentOps))
-	if len(m.opsHistory) > m.maxPoints {
-		m.opsHistory = m.opsHistory[1:]
-	}
-
-	// CPU frequency history
-	if m.currentStats.CPUFreqPct > 0 {
-		m.cpuHistory = append(m.cpuHistory, m.currentStats.CPUFreqPct)
-		if len(m.cpuHistory) > m.maxPoints {
-			m.cpuHistory = m.cpuHistory[1:]
+	m.maxWatts = max(m.maxWatts, sample.PackageWatts)
+
+	// Keep the per-core readings for the per-core panel
+	if len(sample.CoreIDs) > 0 {
+		m.coreIDs = sample.CoreIDs
+		m.coreUtil = sample.CoreUtilization
+		m.coreFreqs = nil
+		if freqs := sample.Stats.CoreFreqs; len(freqs) == len(sample.Stats.CoreTimes) {
+			freqOf := make(map[int]int, len(freqs))
+			for i, t := range sample.Stats.CoreTimes {
+				freqOf[t.ID] = freqs[i]
+			}
+			for _, id := range sample.CoreIDs {
+				m.coreFreqs = append(m.coreFreqs, freqOf[id])
+			}
 		}
 	}
+	m.pinned = m.workerPool.CPUs()
 
-	// Temperature history
-	if m.currentStats.Temperature > 0 {
-		m.tempHistory = append(m.tempHistory, m.currentStats.Temperature)
-		if len(m.tempHistory) > m.maxPoints {
-			m.tempHistory = m.tempHistory[1:]
-		}
-	}
+	// Steady state ends the warmup when the run waits for it
+	m.steady.Add(sample)
+	m.warmup = m.steady.Warmup()
 
-	// Fan speed history (averaged across all fans)
-	if len(m.currentStats.FanRPMs) > 0 {
-		avgFan := 0.0
-		for _, rpm := range m.currentStats.FanRPMs {
-			avgFan += float64(rpm)
-		}
-		avgFan /= float64(len(m.currentStats.FanRPMs))
+	// Update history buffers
+	m.history.add(sample)
 
-		m.fanHistory = append(m.fanHistory, avgFan)
-		if len(m.fanHistory) > m.maxPoints {
-			m.fanHistory = m.fanHistory[1:]
-		}
-	}
+	return m, sampleCmd(m.interval)
 }
 
 // View renders the TUI.
@@ -187,81 +345,220 @@ func (m Model) View() string {
 func (m Model) renderHeader(elapsed time.Duration) string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FF6B35")).
-		Background(lipgloss.Color("#1a1a1a")).
+		Foreground(m.theme.Accent).
+		Background(m.theme.Background).
 		Padding(0, 1)
 
-	// Progress bar
-	progress := float64(elapsed) / float64(m.duration)
-	progressBar := m.renderProgressBar(progress)
+	progressBar := m.renderProgressBar(elapsed)
 
 	title := titleStyle.Render(fmt.Sprintf("🔥 GOBURN"))
 
 	timeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7EC8E3")).
-		Background(lipgloss.Color("#1a1a1a")).
+		Foreground(m.theme.Info).
+		Background(m.theme.Background).
 		Padding(0, 2)
 
 	workerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#98D8C8")).
-		Background(lipgloss.Color("#1a1a1a")).
+		Foreground(m.theme.Secondary).
+		Background(m.theme.Background).
+		Padding(0, 2)
+
+	total := "∞"
+	if m.duration > 0 {
+		total = m.duration.Round(time.Second).String()
+	}
+	timeInfo := timeStyle.Render(fmt.Sprintf("⏱  %s / %s", elapsed, total))
+	workerText := fmt.Sprintf("⚙  %d workers", m.workerPool.GetActiveCount())
+	if m.workerPool.Paused() {
+		workerText += " ⏸ paused"
+	} else if !m.lastEvent.Time.IsZero() {
+		arrow := "▲"
+		if m.lastEvent.Type == worker.WorkerStopped {
+			arrow = "▼"
+		}
+		workerText += fmt.Sprintf(" %s %s", arrow, m.lastEvent.Time.Sub(m.startTime).Round(time.Second))
+	}
+	workerInfo := workerStyle.Render(workerText)
+
+	steadyStyle := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Background(m.theme.Background).
 		Padding(0, 2)
+	steadyText := "≈ settling"
+	if at, ok := m.steady.Steady(); ok {
+		steadyStyle = steadyStyle.Foreground(m.theme.Good).Bold(true)
+		steadyText = "✓ steady since " + formatElapsed(at)
+		if end, ok := m.steady.End(); ok {
+			steadyText += fmt.Sprintf(", ends in %s", formatElapsed(max(end-elapsed, 0)))
+		}
+	}
+	workerInfo = lipgloss.JoinHorizontal(lipgloss.Center, workerInfo, steadyStyle.Render(steadyText))
 
-	timeInfo := timeStyle.Render(fmt.Sprintf("⏱  %s / %s", elapsed, m.duration.Round(time.Second)))
-	workerInfo := workerStyle.Render(fmt.Sprintf("⚙  %d workers", m.workerPool.GetActiveCount()))
+	sampleStyle := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Background(m.theme.Background).
+		Padding(0, 2)
+	_, rows, cols := m.layout()
+	_, graphWidth := m.calculateGraphDimensions(rows, cols)
+	view := m.graphView(graphWidth)
+	sampleText := fmt.Sprintf("⏲  %s samples, %s points, %s",
+		formatElapsed(m.interval), aggregationLabel(view.width), zoomLabel(zooms[m.zoomIndex]))
+	if m.scroll > 0 {
+		sampleText += fmt.Sprintf(" ◀ %s back", formatElapsed(m.latest-view.to))
+	}
+	sampleInfo := sampleStyle.Render(sampleText)
+
+	topLine := lipgloss.JoinHorizontal(lipgloss.Center, title, timeInfo, workerInfo, sampleInfo)
+	if n := len(m.markers); n > 0 {
+		markerStyle := lipgloss.NewStyle().
+			Foreground(m.theme.Text).
+			Background(m.theme.Background).
+			Padding(0, 2)
+		last := m.markers[n-1]
+		topLine = lipgloss.JoinHorizontal(lipgloss.Center, topLine,
+			markerStyle.Render(fmt.Sprintf("⚑ %d %s @ %s", n, last.Label, formatElapsed(last.Elapsed))))
+	}
 
-	topLine := lipgloss.JoinHorizontal(lipgloss.Center, title, timeInfo, workerInfo)
+	if m.cgroupLimits.Limited() {
+		limitStyle := lipgloss.NewStyle().
+			Foreground(m.theme.Warn).
+			Background(m.theme.Background).
+			Padding(0, 2)
+		topLine = lipgloss.JoinHorizontal(lipgloss.Center, topLine,
+			limitStyle.Render("⛓  "+formatCgroupLimits(m.cgroupLimits)))
+	}
 
 	headerStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#FF6B35")).
+		BorderForeground(m.theme.Accent).
 		Padding(0, 1)
 
-	return headerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, topLine, progressBar))
+	lines := []string{topLine}
+	if machine := m.inventory.Short(); machine != "" {
+		machineStyle := lipgloss.NewStyle().
+			Foreground(m.theme.Muted)
+		lines = append(lines, machineStyle.Render("🖳  "+machine))
+	}
+	if m.binding.Pinned() {
+		pinStyle := lipgloss.NewStyle().
+			Foreground(m.theme.Muted)
+		lines = append(lines, pinStyle.Render("📌 "+formatBinding(m.binding, m.pinned)))
+	}
+	if warnings := m.power.Warnings(); len(warnings) > 0 {
+		warnStyle := lipgloss.NewStyle().
+			Foreground(m.theme.Warn).
+			Bold(true)
+		text := "⚠  " + warnings[0]
+		if len(warnings) > 1 {
+			text += fmt.Sprintf(" (+%d more)", len(warnings)-1)
+		}
+		lines = append(lines, warnStyle.Render(text))
+	}
+	lines = append(lines, progressBar)
+
+	return headerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// formatBinding describes the binding and which logical CPUs the pinned
+// workers load.
+func formatBinding(b burn.Binding, pinned []int) string {
+	if len(pinned) == 0 {
+		return b.String() + ", no workers pinned"
+	}
+	text := fmt.Sprintf("%s, workers on CPUs %s", b, hardware.FormatCPUList(pinned))
+	if shared := len(pinned) - len(slices.Compact(slices.Sorted(slices.Values(pinned)))); shared > 0 {
+		text += fmt.Sprintf(" (%d extra workers share CPUs)", shared)
+	}
+	return text
 }
 
-// renderProgressBar creates a gradient progress bar.
-func (m Model) renderProgressBar(progress float64) string {
-	if progress > 1.0 {
-		progress = 1.0
+// formatCgroupLimits describes the cgroup CPU limits for the header.
+func formatCgroupLimits(l hardware.CgroupLimits) string {
+	parts := []string{}
+	if l.CPUQuota > 0 {
+		parts = append(parts, fmt.Sprintf("quota %.2g CPUs", l.CPUQuota))
+	}
+	if l.EffectiveCPUs > 0 {
+		parts = append(parts, fmt.Sprintf("cpuset %d CPUs", l.EffectiveCPUs))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// headerInfoLines returns how many optional machine and warning lines
+// the header shows below the title.
+func (m Model) headerInfoLines() int {
+	n := 0
+	if m.inventory.Short() != "" {
+		n++
+	}
+	if m.binding.Pinned() {
+		n++
 	}
+	if len(m.power.Warnings()) > 0 {
+		n++
+	}
+	return n
+}
 
+// renderProgressBar creates the progress bar of a run at elapsed time.
+// The warmup phase takes the start of the bar, in its own color, and the
+// percentage counts the measurement phase only. The bar of an unlimited
+// run stays empty.
+func (m Model) renderProgressBar(elapsed time.Duration) string {
 	barWidth := 60
 	if m.width > 80 {
 		barWidth = m.width - 30
 	}
 
+	progress, warmupWidth := 0.0, 0
+	if m.duration > 0 {
+		progress = min(float64(elapsed)/float64(m.duration), 1)
+		warmupWidth = min(int(float64(barWidth)*float64(m.warmup)/float64(m.duration)), barWidth)
+	}
 	filled := int(float64(barWidth) * progress)
-	empty := barWidth - filled
+	warmupFilled := min(filled, warmupWidth)
 
-	filledStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF87"))
-	emptyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#444444"))
+	warmupStyle := lipgloss.NewStyle().Foreground(m.theme.Warn)
+	filledStyle := lipgloss.NewStyle().Foreground(m.theme.Good)
+	emptyStyle := lipgloss.NewStyle().Foreground(m.theme.Faint)
 
-	bar := filledStyle.Render(strings.Repeat("█", filled)) +
-		  emptyStyle.Render(strings.Repeat("░", empty))
+	bar := warmupStyle.Render(strings.Repeat("█", warmupFilled)) +
+		emptyStyle.Render(strings.Repeat("▒", warmupWidth-warmupFilled)) +
+		filledStyle.Render(strings.Repeat("█", filled-warmupFilled)) +
+		emptyStyle.Render(strings.Repeat("░", barWidth-max(filled, warmupWidth)))
 
 	percentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
+		Foreground(m.theme.Text).
 		Bold(true)
 
-	return bar + " " + percentStyle.Render(fmt.Sprintf("%.0f%%", progress*100))
+	label := fmt.Sprintf("%.0f%%", progress*100)
+	switch {
+	case elapsed < m.warmup:
+		label = fmt.Sprintf("warmup %s/%s", elapsed, formatElapsed(m.warmup))
+	case m.warmup > 0 && m.duration > m.warmup:
+		measured := float64(elapsed-m.warmup) / float64(m.duration-m.warmup)
+		label = fmt.Sprintf("measuring %.0f%%", min(measured, 1)*100)
+	}
+	return bar + " " + percentStyle.Render(label)
 }
 
-// renderStats creates the current statistics line.
+// renderStats creates the current statistics line, followed by a line
+// per socket on multi-socket machines.
 func (m Model) renderStats() string {
 	// Create stat cards with color-coded values
-	opsCard := m.createStatCard("⚡", "Operations", fmt.Sprintf("%d M/s", m.currentOps), "#FFD700")
+	opsCard := m.createStatCard("⚡", "Operations", fmt.Sprintf("%d M/s", m.currentOps), m.theme.Ops)
 
 	var cpuCard, tempCard, fanCard string
 
 	if m.currentStats.CPUFreqMax > 0 {
-		cpuColor := getPercentageColor(m.currentStats.CPUFreqPct)
+		cpuColor := m.theme.percentColor(m.currentStats.CPUFreqPct)
 		cpuCard = m.createStatCard("🖥", "CPU Freq", fmt.Sprintf("%d MHz", m.currentStats.CPUFreqCur), cpuColor)
 	}
 
 	if m.currentStats.Temperature > 0 {
-		tempColor := getTempColor(m.currentStats.Temperature)
-		tempCard = m.createStatCard("🌡", "Temp", fmt.Sprintf("%.1f°C", m.currentStats.Temperature), tempColor)
+		tempColor := m.theme.tempColor(m.currentStats.Temperature)
+		tempCard = m.createStatCard("🌡", "Temp", fmt.Sprintf("%.1f°C %s",
+			m.currentStats.Temperature, tempLabel(m.currentStats.Temperature)), tempColor)
 	}
 
 	if len(m.currentStats.FanRPMs) > 0 {
@@ -270,7 +567,16 @@ func (m Model) renderStats() string {
 			avgRPM += rpm
 		}
 		avgRPM /= len(m.currentStats.FanRPMs)
-		fanCard = m.createStatCard("🌀", "Fan Avg", fmt.Sprintf("%d RPM", avgRPM), "#00CED1")
+		fanCard = m.createStatCard("🌀", "Fan Avg", fmt.Sprintf("%d RPM", avgRPM), m.theme.Fans)
+	}
+
+	var throttleCard string
+	if m.cgroupLimits.Limited() {
+		throttleColor := m.theme.Good
+		if m.throttledMs > 0 {
+			throttleColor = m.theme.Critical
+		}
+		throttleCard = m.createStatCard("⛓", "Throttled", fmt.Sprintf("%d ms/s", m.throttledMs), throttleColor)
 	}
 
 	cards := []string{opsCard}
@@ -283,27 +589,65 @@ func (m Model) renderStats() string {
 	if fanCard != "" {
 		cards = append(cards, fanCard)
 	}
+	if throttleCard != "" {
+		cards = append(cards, throttleCard)
+	}
 
+	rows := []string{lipgloss.JoinHorizontal(lipgloss.Top, cards...)}
+	for i, sock := range m.sockets {
+		var pkg hardware.PackageStats
+		if i < len(m.currentStats.Packages) {
+			pkg = m.currentStats.Packages[i]
+		}
+		rows = append(rows, m.renderSocketStats(sock, pkg))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderSocketStats creates the statistics line of one socket. Its ops
+// are only known while workers are pinned.
+func (m Model) renderSocketStats(sock burn.SocketSample, pkg hardware.PackageStats) string {
+	name := fmt.Sprintf("Socket %d", sock.Package)
+	ops := "unpinned"
+	if m.pinned != nil {
+		ops = fmt.Sprintf("%d M/s", uint64(sock.OpsPerSec/1_000_000))
+	}
+	cards := []string{m.createStatCard("⚡", name+" Ops", ops, m.theme.Ops)}
+	if sock.CPUFreqMHz > 0 {
+		cards = append(cards, m.createStatCard("🖥", name+" Freq",
+			fmt.Sprintf("%d MHz", sock.CPUFreqMHz), m.theme.percentColor(pkg.CPUFreqPct)))
+	}
+	if sock.Temperature > 0 {
+		cards = append(cards, m.createStatCard("🌡", name+" Temp",
+			fmt.Sprintf("%.1f°C %s", sock.Temperature, tempLabel(sock.Temperature)), m.theme.tempColor(sock.Temperature)))
+	}
+	if sock.PackageWatts > 0 {
+		cards = append(cards, m.createStatCard("🔌", name+" Power",
+			fmt.Sprintf("%.1f W", sock.PackageWatts), m.theme.PowerLine))
+	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, cards...)
 }
 
+// statCardHeight is the height of a stat card: two lines and a border.
+const statCardHeight = 4
+
 // createStatCard creates a styled stat card.
-func (m Model) createStatCard(icon, label, value, color string) string {
+func (m Model) createStatCard(icon, label, value string, color lipgloss.TerminalColor) string {
 	cardStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(color)).
+		BorderForeground(color).
 		Padding(0, 1).
 		Margin(0, 0, 0, 1)
 
 	iconStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(color)).
+		Foreground(color).
 		Bold(true)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888888"))
+		Foreground(m.theme.Muted)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(color)).
+		Foreground(color).
 		Bold(true)
 
 	content := fmt.Sprintf("%s %s\n%s",
@@ -314,102 +658,92 @@ func (m Model) createStatCard(icon, label, value, color string) string {
 	return cardStyle.Render(content)
 }
 
-// getPercentageColor returns color based on percentage value.
-func getPercentageColor(pct float64) string {
-	if pct < 50 {
-		return "#00FF87"
-	} else if pct < 75 {
-		return "#FFD700"
-	}
-	return "#FF6B35"
-}
-
-// getTempColor returns color based on temperature.
-func getTempColor(temp float64) string {
-	if temp < 50 {
-		return "#00FF87"
-	} else if temp < 70 {
-		return "#FFD700"
-	} else if temp < 85 {
-		return "#FF8C00"
-	}
-	return "#FF0000"
-}
-
-// renderGraphs creates the 2x2 graph panel layout.
+// renderGraphs lays out the visible panels in a grid of two columns,
+// or the focused panel alone when it is maximized.
 func (m Model) renderGraphs() string {
-	graphHeight, graphWidth := m.calculateGraphDimensions()
-
-	// Calculate Y-axis bounds for each graph
-	maxOpsY := float64(m.maxOps) * 1.2
-	if maxOpsY < 100 {
-		maxOpsY = 100
+	names, rows, cols := m.layout()
+	graphHeight, graphWidth := m.calculateGraphDimensions(rows, cols)
+	v := m.graphView(graphWidth)
+	focused := ""
+	if visible := m.visiblePanels(); m.focus < len(visible) {
+		focused = visible[m.focus]
 	}
 
-	maxFanY := float64(m.maxFanRPM) * 1.2
-	if maxFanY < 6000 {
-		maxFanY = 6000
+	panels := make([]string, len(names))
+	for i, name := range names {
+		def := panelDefs[name]
+		if def.render != nil {
+			panels[i] = def.render(m, def.title, graphHeight, graphWidth, name == focused)
+			continue
+		}
+		buckets := def.track(m.history).window(v.from, v.to, v.width)
+		panels[i] = m.renderGraph(def.title, buckets, v, 0, def.upper(m), graphHeight, graphWidth, name == focused)
 	}
 
-	// Create individual graphs
-	graph1 := m.renderGraph("Operations (M/s)", m.opsHistory, 0, maxOpsY, graphHeight, graphWidth)
-	graph2 := m.renderGraph("CPU Frequency (%)", m.cpuHistory, 0, 100.0, graphHeight, graphWidth)
-	graph3 := m.renderGraph("Temperature (°C)", m.tempHistory, 0, 100.0, graphHeight, graphWidth)
-	graph4 := m.renderGraph("Fan Speed (RPM avg)", m.fanHistory, 0, maxFanY, graphHeight, graphWidth)
-
-	// Layout in 2x2 grid
-	row1 := lipgloss.JoinHorizontal(lipgloss.Top, graph1, graph2)
-	row2 := lipgloss.JoinHorizontal(lipgloss.Top, graph3, graph4)
+	// Layout in a grid, row by row
+	var grid []string
+	for row := 0; row < len(panels); row += cols {
+		grid = append(grid, lipgloss.JoinHorizontal(lipgloss.Top, panels[row:min(row+cols, len(panels))]...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, grid...)
+}
 
-	return lipgloss.JoinVertical(lipgloss.Left, row1, row2)
+// graphView returns the time range the graphs show at the selected zoom,
+// scroll and aggregation, for graphs of the given width.
+func (m Model) graphView(width int) graphView {
+	return newGraphView(m.latest, zooms[m.zoomIndex], m.scroll, aggregations[m.aggIndex], width)
 }
 
-// renderGraph creates a single graph panel.
-func (m Model) renderGraph(title string, data []float64, minY, maxY float64, height, width int) string {
+// renderGraph creates a single graph panel of the buckets in view.
+func (m Model) renderGraph(title string, buckets []bucket, v graphView, minY, maxY float64, height, width int, focused bool) string {
+	data := averages(buckets)
+
 	// Determine color based on graph type
-	var borderColor, graphColor string
+	var borderColor, graphColor lipgloss.TerminalColor
 	switch {
 	case strings.Contains(title, "Operations"):
-		borderColor = "#FFD700"
-		graphColor = "#FFD700"
-	case strings.Contains(title, "CPU"):
-		borderColor = "#7EC8E3"
-		graphColor = "#00CED1"
+		borderColor = m.theme.Ops
+		graphColor = m.theme.Ops
+	case strings.Contains(title, "CPU Freq"):
+		borderColor = m.theme.Info
+		graphColor = m.theme.FreqLine
 	case strings.Contains(title, "Temperature"):
-		// Dynamic color based on current temp
+		// Dynamic color and label based on current temp
+		borderColor = m.theme.Good
+		graphColor = m.theme.Good
 		if len(data) > 0 {
 			currentTemp := data[len(data)-1]
-			borderColor = getTempColor(currentTemp)
-			graphColor = getTempColor(currentTemp)
-		} else {
-			borderColor = "#00FF87"
-			graphColor = "#00FF87"
+			borderColor = m.theme.tempColor(currentTemp)
+			graphColor = m.theme.tempColor(currentTemp)
+			title += " " + tempLabel(currentTemp)
 		}
 	case strings.Contains(title, "Fan"):
-		borderColor = "#98D8C8"
-		graphColor = "#5FD7FF"
+		borderColor = m.theme.Secondary
+		graphColor = m.theme.FanLine
+	case strings.Contains(title, "Power"):
+		borderColor = m.theme.Hot
+		graphColor = m.theme.PowerLine
+	case strings.Contains(title, "Utilization"):
+		borderColor = m.theme.Secondary
+		graphColor = m.theme.Good
 	default:
-		borderColor = "#888888"
-		graphColor = "#FFFFFF"
+		borderColor = m.theme.Muted
+		graphColor = m.theme.Text
 	}
 
-	// Fixed width for consistent panel sizing
-	panelWidth := width + 10
-	panelStyle := lipgloss.NewStyle().
-		Border(lipgloss.DoubleBorder()).
-		BorderForeground(lipgloss.Color(borderColor)).
-		Padding(1, 1).
-		Width(panelWidth).
-		Height(height + 8) // Fixed height for alignment
+	panelStyle := m.panelStyle(borderColor, height, width, focused)
 
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color(borderColor)).
-		Background(lipgloss.Color("#1a1a1a")).
+		Foreground(borderColor).
+		Background(m.theme.Background).
 		Padding(0, 1)
 
 	graphStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(graphColor))
+		Foreground(graphColor)
+
+	axisStyle := lipgloss.NewStyle().
+		Foreground(m.theme.Muted)
 
 	var g strings.Builder
 	g.WriteString(labelStyle.Render(title))
@@ -421,19 +755,25 @@ func (m Model) renderGraph(title string, data []float64, minY, maxY float64, hei
 			asciigraph.Width(width),
 			asciigraph.LowerBound(minY),
 			asciigraph.UpperBound(maxY))
+		graph = drawMarkers(graph, v.from, v.to, m.markers)
 		g.WriteString(graphStyle.Render(graph))
+		g.WriteString("\n")
+		g.WriteString(axisStyle.Render(renderTimeAxis(graph, v.from, v.to, m.pausePeriods(), m.markers)))
 
-		// Add current value indicator
-		currentVal := data[len(data)-1]
+		// Add last value indicator, with the range of the view
+		lo, hi := buckets[0].min, buckets[0].max
+		for _, b := range buckets {
+			lo, hi = min(lo, b.min), max(hi, b.max)
+		}
 		currentStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color(graphColor)).
-			Bold(true).
-			MarginTop(1)
+			Foreground(graphColor).
+			Bold(true)
 		g.WriteString("\n")
-		g.WriteString(currentStyle.Render(fmt.Sprintf("▶ %.1f", currentVal)))
+		g.WriteString(currentStyle.Render(fmt.Sprintf("▶ %.1f", data[len(data)-1])))
+		g.WriteString(axisStyle.Render(fmt.Sprintf("  ↓%.1f ↑%.1f", lo, hi)))
 	} else {
 		waitStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
+			Foreground(m.theme.Muted).
 			Italic(true)
 		g.WriteString(waitStyle.Render("⏳ Collecting data..."))
 	}
@@ -444,15 +784,37 @@ func (m Model) renderGraph(title string, data []float64, minY, maxY float64, hei
 // renderHelp creates the help text.
 func (m Model) renderHelp() string {
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF6B35")).
+		Foreground(m.theme.Accent).
 		Bold(true).
 		Padding(0, 1)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888888"))
+		Foreground(m.theme.Muted)
 
 	dividerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#444444"))
+		Foreground(m.theme.Faint)
+
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Faint).
+		Padding(0, 1)
+
+	// The label of a marker being dropped replaces the key list
+	if m.markLabel != nil {
+		labelStyle := lipgloss.NewStyle().
+			Foreground(m.theme.Text).
+			Bold(true)
+		return containerStyle.Render(lipgloss.JoinHorizontal(lipgloss.Center,
+			descStyle.Render(fmt.Sprintf("⚑ marker %d: ", len(m.markers)+1)),
+			labelStyle.Render(string(m.markLabel)+"▏"),
+			dividerStyle.Render(" • "),
+			keyStyle.Render("enter"),
+			descStyle.Render("drop"),
+			dividerStyle.Render(" • "),
+			keyStyle.Render("esc"),
+			descStyle.Render("cancel"),
+		))
+	}
 
 	help := lipgloss.JoinHorizontal(lipgloss.Center,
 		keyStyle.Render("+"),
@@ -461,72 +823,263 @@ func (m Model) renderHelp() string {
 		keyStyle.Render("-"),
 		descStyle.Render("decrease"),
 		dividerStyle.Render(" • "),
+		keyStyle.Render("p"),
+		descStyle.Render("pause"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("t/T"),
+		descStyle.Render("±1m"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("u"),
+		descStyle.Render("unlimited"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("m"),
+		descStyle.Render("marker"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("a"),
+		descStyle.Render("aggregate"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("z"),
+		descStyle.Render("zoom"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("←/→"),
+		descStyle.Render("scroll"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("tab"),
+		descStyle.Render("focus"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("enter"),
+		descStyle.Render("maximize"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("</>"),
+		descStyle.Render("move"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("x"),
+		descStyle.Render("hide"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("k"),
+		descStyle.Render("cores"),
+		dividerStyle.Render(" • "),
+		keyStyle.Render("c"),
+		descStyle.Render("correlate"),
+		dividerStyle.Render(" • "),
 		keyStyle.Render("q"),
 		descStyle.Render("quit"),
 	)
 
-	containerStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#444444")).
-		Padding(0, 1)
-
 	return containerStyle.Render(help)
 }
 
-// calculateGraphDimensions determines optimal graph size based on terminal dimensions.
-func (m Model) calculateGraphDimensions() (height, width int) {
+// pausePeriods returns when the workers were paused, with an ongoing pause
+// ending at the last sample.
+func (m Model) pausePeriods() []period {
+	pauses := slices.Clone(m.pauses)
+	for i, p := range pauses {
+		if p.to < 0 {
+			pauses[i].to = max(m.latest, p.from)
+		}
+	}
+	return pauses
+}
+
+// plotArea returns the first column and the width of the plot area of an
+// asciigraph graph.
+func plotArea(graph string) (start, width int) {
+	// The plot starts right after the y-axis and ends with the longest line.
+	// Series colors only appear after the axis.
+	lineWidth := 0
+	for _, l := range strings.Split(graph, "\n") {
+		lineWidth = max(lineWidth, lipgloss.Width(l))
+		for i, r := range []rune(l) {
+			if r == '┤' || r == '┼' {
+				start = i + 1
+				break
+			}
+		}
+	}
+	return start, lineWidth - start
+}
+
+// plotColumn returns the column of elapsed time at in a plot area showing
+// from to to.
+func plotColumn(at, from, to time.Duration, start, width int) int {
+	return start + int(float64(at-from)/float64(to-from)*float64(width-1))
+}
+
+// renderTimeAxis returns a line labelling the plot area of an asciigraph
+// graph with the elapsed times at its start, middle and end. The columns
+// of pauses are shaded, and markers are numbered from 1.
+func renderTimeAxis(graph string, from, to time.Duration, pauses []period, markers []burn.Marker) string {
+	plotStart, plotWidth := plotArea(graph)
+
+	left, mid, right := formatElapsed(from), formatElapsed((from+to)/2), formatElapsed(to)
+	line := []rune(strings.Repeat(" ", plotStart+plotWidth))
+	column := func(at time.Duration) int {
+		return plotColumn(at, from, to, plotStart, plotWidth)
+	}
+	for _, p := range pauses {
+		if to <= from || p.to < from || p.from > to {
+			continue
+		}
+		for i := column(max(p.from, from)); i <= column(min(p.to, to)); i++ {
+			line[i] = '▒'
+		}
+	}
+	place := func(label string, at int) {
+		at = max(min(at, len(line)-len(label)), 0)
+		copy(line[at:], []rune(label))
+	}
+	place(left, plotStart)
+	if plotWidth > len(left)+len(mid)+len(right)+4 {
+		place(mid, plotStart+(plotWidth-len(mid))/2)
+	}
+	if plotWidth > len(left)+len(right)+1 {
+		place(right, len(line)-len(right))
+	}
+	for i, mk := range markers {
+		if to > from && mk.Elapsed >= from && mk.Elapsed <= to {
+			place(strconv.Itoa(i+1), column(mk.Elapsed))
+		}
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+// calculateGraphDimensions determines optimal graph size based on terminal
+// dimensions, for a grid of rows by cols panels.
+func (m Model) calculateGraphDimensions(rows, cols int) (height, width int) {
 	height = 15
 	width = 50
 
 	if m.width > 0 && m.height > 0 {
 		// Account for UI overhead (header + stats + help + spacing)
-		uiOverhead := 14      // header, stats, help lines + spacing
+		uiOverhead := 14 + m.headerInfoLines() // header, stats, help lines + spacing
+		uiOverhead += statCardHeight * len(m.sockets)
 		panelBorderHeight := 8 // borders and padding per panel
 		availableHeight := m.height - uiOverhead
 
-		// Divide by 2 for two rows of graphs
-		height = (availableHeight / 2) - panelBorderHeight
+		// Divide between the rows of graphs
+		height = (availableHeight / rows) - panelBorderHeight
 		if height < 6 {
 			height = 6
 		}
-		if height > 20 {
-			height = 20
+		if height > 40/rows {
+			height = max(40/rows, 6)
 		}
 
-		// Calculate width for two columns
-		panelBorderWidth := 8  // borders and padding per panel
+		// Calculate width for the columns
+		panelBorderWidth := 8 // borders and padding per panel
 		availableWidth := m.width
-		width = (availableWidth / 2) - panelBorderWidth
+		width = (availableWidth / cols) - panelBorderWidth
 		if width < 30 {
 			width = 30
 		}
-		if width > 70 {
-			width = 70
+		if width > 140/cols {
+			width = 140 / cols
 		}
 	}
 
 	return height, width
 }
 
-// RunGraphMode starts the interactive TUI.
-func RunGraphMode(counter *uint64, duration time.Duration, startTime time.Time, initialWorkers int) {
-	wp := worker.New(counter, initialWorkers)
+// GraphConfig describes a graph mode run.
+type GraphConfig struct {
+	Duration time.Duration // Run length, 0 to run until quit
+	Warmup   time.Duration // Initial part left out of the summary
+	// UntilSteady ends the run this long after steady state is detected,
+	// and extends the warmup to the detection. 0 runs for Duration.
+	UntilSteady time.Duration
+	Start       time.Time              // When the workers started
+	Interval    time.Duration          // Sample interval, 0 for one second
+	Limits      hardware.CgroupLimits  // Cgroup limits shown in the header
+	Power       hardware.PowerSettings // Power settings read before the workers started
+	Binding     burn.Binding           // How the pool pins workers, zero if not
+	Panels      []string               // Panels in display order, nil for panel.Defaults
+	Theme       Theme                  // Colors, zero for the "auto" theme
+}
+
+// RunGraphMode starts the interactive TUI controlling the given worker pool,
+// until the user quits, the duration ends or ctx is cancelled. It returns
+// the report of everything sampled while the TUI was running, after printing
+// its summary and markers as replay does; if ctx was cancelled, together
+// with ctx.Err().
+func RunGraphMode(ctx context.Context, wp *worker.Pool, cfg GraphConfig) (burn.Report, error) {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	theme := cfg.Theme
+	if theme.Name == "" {
+		theme, _ = LookupTheme("auto")
+	}
+
+	panels := cfg.Panels
+	if len(panels) == 0 {
+		panels = panel.Defaults
+	}
+
+	inventory := hardware.GetInventory()
+	power := cfg.Power
+	events, unsubscribe := wp.Subscribe(16)
+	defer unsubscribe()
+
+	sampler := burn.NewSampler(wp, cfg.Start)
+	sampler.SetWarmup(cfg.Warmup)
+	recorder := burn.NewRecorder(burn.Report{
+		Start:     cfg.Start,
+		Duration:  cfg.Duration,
+		Warmup:    cfg.Warmup,
+		Workers:   wp.GetActiveCount(),
+		Binding:   cfg.Binding,
+		Inventory: inventory,
+		Power:     power,
+		Cgroup:    cfg.Limits,
+	})
 
 	m := Model{
-		workerPool:  wp,
-		lastCounter: 0,
-		startTime:   startTime,
-		duration:    duration,
-		maxPoints:   60,
-		maxOps:      10,
-		maxFanRPM:   1000,
-		width:       120,
-		height:      30,
+		workerPool:       wp,
+		sampler:          sampler,
+		recorder:         recorder,
+		inventory:        inventory,
+		power:            power,
+		workerEvents:     events,
+		cgroupLimits:     cfg.Limits,
+		binding:          cfg.Binding,
+		pinned:           wp.CPUs(),
+		startTime:        cfg.Start,
+		duration:         cfg.Duration,
+		warmup:           cfg.Warmup,
+		steady:           burn.NewSteadyTracker(sampler, recorder, cfg.Warmup, cfg.UntilSteady),
+		interval:         interval,
+		history:          newHistory(interval),
+		panels:           panels,
+		theme:            theme,
+		configuredPanels: panels,
+		aggIndex:         defaultAggregation(interval),
+		maxOps:           10,
+		maxFanRPM:        1000,
+		width:            120,
+		height:           30,
 	}
 
 	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
-		os.Exit(1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.Quit()
+		case <-done:
+		}
+	}()
+	final, err := p.Run()
+	if err != nil {
+		return m.recorder.Report(time.Now()), fmt.Errorf("running TUI: %w", err)
+	}
+	// The run may have been extended or made unlimited from the TUI
+	if fm, ok := final.(Model); ok {
+		m.recorder.SetDuration(fm.duration)
 	}
+	report := m.recorder.Report(time.Now())
+	printSummary(report)
+	return report, ctx.Err()
 }
@@ -0,0 +1,39 @@
+//go:build linux
+
+package worker
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// cpuMask is a sched_setaffinity CPU mask of CPU_SETSIZE (1024) CPUs.
+type cpuMask [16]uint64
+
+// pinThread binds the calling OS thread to a logical CPU.
+func pinThread(cpu int) error {
+	var mask cpuMask
+	if cpu < 0 || cpu >= 64*len(mask) {
+		return fmt.Errorf("pin to CPU %d: out of range", cpu)
+	}
+	mask[cpu/64] |= 1 << (cpu % 64)
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0, unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// allowedCPUs reports which logical CPUs the calling thread may run on.
+// Returns nil if the affinity mask cannot be read.
+func allowedCPUs() func(cpu int) bool {
+	var mask cpuMask
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY, 0, unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask)))
+	if errno != 0 {
+		return nil
+	}
+	return func(cpu int) bool {
+		return cpu >= 0 && cpu < 64*len(mask) && mask[cpu/64]&(1<<(cpu%64)) != 0
+	}
+}
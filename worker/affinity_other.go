@@ -0,0 +1,15 @@
+//go:build !linux
+
+package worker
+
+import "errors"
+
+// pinThread is not supported outside Linux; workers run unpinned.
+func pinThread(cpu int) error {
+	return errors.ErrUnsupported
+}
+
+// allowedCPUs returns nil: the affinity mask is unknown outside Linux.
+func allowedCPUs() func(cpu int) bool {
+	return nil
+}
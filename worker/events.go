@@ -0,0 +1,101 @@
+package worker
+
+import "time"
+
+// EventType identifies a kind of worker lifecycle event.
+type EventType int
+
+const (
+	// WorkerStarted is sent when a worker goroutine is spawned.
+	WorkerStarted EventType = iota
+	// WorkerStopped is sent when a worker is signalled to stop.
+	WorkerStopped
+	// Paused is sent when the pool is paused. Worker is -1.
+	Paused
+	// Resumed is sent when the pool resumes after a pause. Worker is -1.
+	Resumed
+)
+
+// String returns a short lowercase name for the event type.
+func (t EventType) String() string {
+	switch t {
+	case WorkerStarted:
+		return "started"
+	case WorkerStopped:
+		return "stopped"
+	case Paused:
+		return "paused"
+	case Resumed:
+		return "resumed"
+	}
+	return "unknown"
+}
+
+// Event describes a single worker starting or stopping, or the whole
+// pool pausing or resuming.
+type Event struct {
+	Type   EventType
+	Worker int       // Worker slot, 0 for the first worker spawned
+	Active int       // Active workers right after the event
+	Time   time.Time // When the control path applied the change
+}
+
+// Subscribe returns a channel that receives every worker event from now on,
+// in the order the pool applied them. Events are dropped rather than
+// blocking the pool when the buffer is full, so slow subscribers should
+// resynchronise with GetActiveCount. The channel is closed when the pool
+// stops or the returned cancel function is called.
+func (wp *Pool) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.stopped {
+		close(ch)
+		return ch, func() {}
+	}
+	wp.subscribers = append(wp.subscribers, ch)
+
+	cancel := func() {
+		wp.mu.Lock()
+		defer wp.mu.Unlock()
+		for i, sub := range wp.subscribers {
+			if sub == ch {
+				wp.subscribers = append(wp.subscribers[:i], wp.subscribers[i+1:]...)
+				close(ch)
+				return
+			}
+		}
+	}
+	return ch, cancel
+}
+
+// publish sends an event to every subscriber without blocking.
+// The caller must hold wp.mu.
+func (wp *Pool) publish(typ EventType, worker int) {
+	if len(wp.subscribers) == 0 {
+		return
+	}
+	ev := Event{
+		Type:   typ,
+		Worker: worker,
+		Active: len(wp.stopChannels),
+		Time:   time.Now(),
+	}
+	for _, ch := range wp.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// closeSubscribers closes and forgets every subscriber channel.
+// The caller must hold wp.mu.
+func (wp *Pool) closeSubscribers() {
+	for _, ch := range wp.subscribers {
+		close(ch)
+	}
+	wp.subscribers = nil
+}
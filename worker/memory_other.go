@@ -0,0 +1,9 @@
+//go:build !linux
+
+package worker
+
+// newBuffer returns a zeroed buffer of words 8-byte words from the heap;
+// workers are not pinned outside Linux, so its placement does not matter.
+func newBuffer(words int) (buf []uint64, release func()) {
+	return make([]uint64, words), func() {}
+}
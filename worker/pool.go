@@ -3,38 +3,164 @@
 package worker
 
 import (
-	"math"
+	"context"
 	"math/rand"
 	"runtime"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // Pool manages a collection of CPU-intensive worker goroutines.
 // Workers can be dynamically added or removed to adjust CPU load.
+// A pool lives until Stop is called or its context is cancelled.
+// All control methods are safe to call from any number of goroutines;
+// they are serialized so each one sees the effect of the previous.
 type Pool struct {
-	counter      *uint64      // Shared operation counter
-	stopChannels []chan bool  // Stop signals for each worker
-	activeCount  int32        // Current number of active workers
+	counter      *uint64         // Shared operation counter
+	ctx          context.Context // Cancelled when the pool stops
+	cancel       context.CancelFunc
+	mu           sync.Mutex      // Serializes every control operation
+	stopChannels []chan struct{} // Stop signals for each worker
+	stopped      bool            // Set once Stop has been called
+	subscribers  []chan Event    // Worker event subscribers
+	activeCount  int32           // Current number of active workers
+	wg           sync.WaitGroup  // Tracks running worker goroutines
+
+	pauseGate   atomic.Pointer[chan struct{}] // Closed on resume, nil while running
+	pausedAt    time.Time                     // Start of the current pause
+	pausedTotal time.Duration                 // Length of the finished pauses
+
+	workload    Workload                  // Kernel run by every worker
+	manageProcs bool                      // Keep runtime.GOMAXPROCS equal to the worker count
+	cpus        []int                     // Logical CPU of each worker index, nil for unpinned
+	memCPU      func(worker, cpu int) int // CPU a worker touches its memory from, nil for its own
+	cpuOps      map[int]*atomic.Uint64    // Operations of pinned workers per CPU
+	origProcs   int                       // GOMAXPROCS before the pool changed it
+}
+
+// Option configures optional Pool behaviour.
+type Option func(*Pool)
+
+// WithGOMAXPROCS makes the pool set runtime.GOMAXPROCS to the worker count
+// on every SetWorkers call, and restore the original value on Stop.
+// Without it the pool never touches GOMAXPROCS.
+func WithGOMAXPROCS() Option {
+	return func(wp *Pool) {
+		wp.manageProcs = true
+	}
+}
+
+// WithCPUs pins worker i to logical CPU cpus[i%len(cpus)], such as the
+// order of a hardware.Placement. Negative CPUs and CPUs outside the
+// process's affinity mask (a cgroup cpuset or taskset) are dropped.
+// Pinning is best effort: a worker whose thread cannot be pinned runs
+// unpinned.
+func WithCPUs(cpus []int) Option {
+	return func(wp *Pool) {
+		allowed := allowedCPUs()
+		wp.cpus = nil
+		for _, cpu := range cpus {
+			if cpu >= 0 && (allowed == nil || allowed(cpu)) {
+				wp.cpus = append(wp.cpus, cpu)
+			}
+		}
+		wp.cpuOps = make(map[int]*atomic.Uint64, len(wp.cpus))
+		for _, cpu := range wp.cpus {
+			wp.cpuOps[cpu] = new(atomic.Uint64)
+		}
+	}
+}
+
+// WithMemoryCPUs makes every pinned worker set up its workload (see
+// Workload.NewRun) on the logical CPU that memCPU returns for the worker's
+// index and CPU, before moving to its own CPU. Linux places memory on
+// the NUMA node of the CPU that touches it first, so this chooses the
+// node of the worker's memory. A negative result keeps the worker's own
+// CPU. Without WithCPUs it has no effect.
+func WithMemoryCPUs(memCPU func(worker, cpu int) int) Option {
+	return func(wp *Pool) {
+		wp.memCPU = memCPU
+	}
 }
 
 // New creates a new worker pool with the specified number of initial workers.
 // The counter parameter is a shared atomic counter that workers increment.
-func New(counter *uint64, initialWorkers int) *Pool {
+// All workers exit when ctx is cancelled or Stop is called.
+func New(ctx context.Context, counter *uint64, initialWorkers int, opts ...Option) *Pool {
+	ctx, cancel := context.WithCancel(ctx)
 	wp := &Pool{
 		counter:      counter,
-		stopChannels: make([]chan bool, 0),
+		ctx:          ctx,
+		cancel:       cancel,
+		stopChannels: make([]chan struct{}, 0),
 		activeCount:  0,
+		workload:     workloads[DefaultWorkload],
+		origProcs:    runtime.GOMAXPROCS(-1),
+	}
+	for _, opt := range opts {
+		opt(wp)
 	}
 	wp.SetWorkers(initialWorkers)
+
+	// Cancelling the parent context stops the pool like Stop does
+	context.AfterFunc(ctx, wp.Stop)
 	return wp
 }
 
 // SetWorkers adjusts the pool to have exactly the target number of workers.
 // If target > current, new workers are spawned.
 // If target < current, excess workers are stopped gracefully.
+// It is safe to call from multiple goroutines, and does nothing once the
+// pool has stopped.
 func (wp *Pool) SetWorkers(target int) {
-	current := int(atomic.LoadInt32(&wp.activeCount))
+	if target < 0 {
+		target = 0
+	}
 
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	wp.setWorkers(target)
+}
+
+// AddWorkers changes the worker count by delta, which may be negative,
+// and returns the resulting count. The count never drops below zero.
+// Unlike SetWorkers(GetActiveCount()+delta), the read and the change
+// happen atomically with respect to other callers.
+func (wp *Pool) AddWorkers(delta int) int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	target := len(wp.stopChannels) + delta
+	if target < 0 {
+		target = 0
+	}
+	wp.setWorkers(target)
+	return len(wp.stopChannels)
+}
+
+// RemoveWorkers stops up to n workers while keeping at least floor, and
+// returns the resulting count. A pool already at or below floor is left
+// alone. Like AddWorkers, the check and the change are atomic.
+func (wp *Pool) RemoveWorkers(n, floor int) int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	current := len(wp.stopChannels)
+	if target := max(current-n, floor); target < current {
+		wp.setWorkers(target)
+	}
+	return len(wp.stopChannels)
+}
+
+// setWorkers implements SetWorkers. The caller must hold wp.mu.
+func (wp *Pool) setWorkers(target int) {
+	if wp.stopped || wp.ctx.Err() != nil {
+		return
+	}
+
+	current := len(wp.stopChannels)
 	if target > current {
 		// Spawn additional workers
 		wp.spawnWorkers(target - current)
@@ -43,8 +169,79 @@ func (wp *Pool) SetWorkers(target int) {
 		wp.stopWorkers(current - target)
 	}
 
-	// Update runtime GOMAXPROCS to match worker count
-	runtime.GOMAXPROCS(target)
+	if wp.manageProcs && target > 0 {
+		runtime.GOMAXPROCS(target)
+	}
+}
+
+// Stop signals every worker to exit and waits until they have returned.
+// It is safe to call more than once.
+func (wp *Pool) Stop() {
+	wp.mu.Lock()
+	if !wp.stopped {
+		wp.stopped = true
+		wp.cancel()
+		wp.stopWorkers(len(wp.stopChannels))
+		wp.closeSubscribers()
+		if wp.manageProcs {
+			runtime.GOMAXPROCS(wp.origProcs)
+		}
+	}
+	wp.mu.Unlock()
+
+	wp.wg.Wait()
+}
+
+// Pause makes every worker, including ones added later, idle until Resume
+// is called. Workers keep their slots, so the worker count is unchanged.
+// Returns false if the pool is already paused or has stopped.
+func (wp *Pool) Pause() bool {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.stopped || wp.pauseGate.Load() != nil {
+		return false
+	}
+	gate := make(chan struct{})
+	wp.pauseGate.Store(&gate)
+	wp.pausedAt = time.Now()
+	wp.publish(Paused, -1)
+	return true
+}
+
+// Resume restarts the workers after Pause.
+// Returns false if the pool is not paused.
+func (wp *Pool) Resume() bool {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	gate := wp.pauseGate.Load()
+	if gate == nil {
+		return false
+	}
+	close(*gate)
+	wp.pauseGate.Store(nil)
+	wp.pausedTotal += time.Since(wp.pausedAt)
+	wp.publish(Resumed, -1)
+	return true
+}
+
+// Paused reports whether the pool is paused.
+func (wp *Pool) Paused() bool {
+	return wp.pauseGate.Load() != nil
+}
+
+// PausedTime returns how long the pool has been paused in total,
+// including the current pause.
+func (wp *Pool) PausedTime() time.Duration {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	total := wp.pausedTotal
+	if wp.pauseGate.Load() != nil {
+		total += time.Since(wp.pausedAt)
+	}
+	return total
 }
 
 // GetActiveCount returns the current number of active workers.
@@ -52,60 +249,149 @@ func (wp *Pool) GetActiveCount() int {
 	return int(atomic.LoadInt32(&wp.activeCount))
 }
 
+// CPUs returns the logical CPU of every active worker, in worker order,
+// or nil if workers are not pinned. A CPU shows up once per worker on it.
+func (wp *Pool) CPUs() []int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if len(wp.cpus) == 0 {
+		return nil
+	}
+	cpus := make([]int, len(wp.stopChannels))
+	for i := range cpus {
+		cpus[i] = wp.cpus[i%len(wp.cpus)]
+	}
+	return cpus
+}
+
+// CPUOps returns the cumulative operations of the pinned workers on each
+// logical CPU, or nil if workers are not pinned.
+func (wp *Pool) CPUOps() map[int]uint64 {
+	if len(wp.cpuOps) == 0 {
+		return nil
+	}
+	ops := make(map[int]uint64, len(wp.cpuOps))
+	for cpu, n := range wp.cpuOps {
+		ops[cpu] = n.Load()
+	}
+	return ops
+}
+
 // GetCounter returns a pointer to the shared operation counter.
 func (wp *Pool) GetCounter() *uint64 {
 	return wp.counter
 }
 
 // spawnWorkers creates n new worker goroutines.
+// The caller must hold wp.mu.
 func (wp *Pool) spawnWorkers(n int) {
 	for i := 0; i < n; i++ {
-		stopCh := make(chan bool, 1)
+		stopCh := make(chan struct{})
 		wp.stopChannels = append(wp.stopChannels, stopCh)
 		atomic.AddInt32(&wp.activeCount, 1)
 
-		go wp.runWorker(stopCh)
+		index := len(wp.stopChannels) - 1
+		cpu, memCPU := -1, -1
+		if len(wp.cpus) > 0 {
+			cpu = wp.cpus[index%len(wp.cpus)]
+			if wp.memCPU != nil {
+				memCPU = wp.memCPU(index, cpu)
+			}
+		}
+
+		wp.wg.Add(1)
+		go wp.runWorker(stopCh, cpu, memCPU)
+		wp.publish(WorkerStarted, index)
 	}
 }
 
-// stopWorkers signals n workers to stop and removes their channels.
+// stopWorkers signals the n most recently spawned workers to stop and
+// removes their channels. The caller must hold wp.mu.
 func (wp *Pool) stopWorkers(n int) {
-	current := int(atomic.LoadInt32(&wp.activeCount))
-
-	for i := 0; i < n && i < len(wp.stopChannels); i++ {
-		idx := current - 1 - i
-		if idx >= 0 && idx < len(wp.stopChannels) {
-			wp.stopChannels[idx] <- true
-			atomic.AddInt32(&wp.activeCount, -1)
-		}
+	if n > len(wp.stopChannels) {
+		n = len(wp.stopChannels)
 	}
 
-	// Clean up stopped channels
-	newCount := int(atomic.LoadInt32(&wp.activeCount))
-	if newCount >= 0 && newCount < len(wp.stopChannels) {
-		wp.stopChannels = wp.stopChannels[:newCount]
+	for i := 0; i < n; i++ {
+		last := len(wp.stopChannels) - 1
+		close(wp.stopChannels[last])
+		wp.stopChannels = wp.stopChannels[:last]
+		atomic.AddInt32(&wp.activeCount, -1)
+		wp.publish(WorkerStopped, last)
 	}
 }
 
+// batchSize is the number of workload operations between stop checks.
+const batchSize = 10_000
+
+// flushSize is the number of operations a worker accumulates before adding
+// them to the shared counter. It keeps sub-second samples accurate without
+// contending on the counter's cache line.
+const flushSize = 100_000
+
 // runWorker executes CPU-intensive operations until signaled to stop.
-// It performs floating-point math operations to generate CPU load.
-func (wp *Pool) runWorker(stopCh chan bool) {
+// It runs the pool's workload in small batches to generate CPU load,
+// pinned to logical CPU cpu unless it is negative. A non-negative memCPU
+// is where the workload sets up its memory.
+func (wp *Pool) runWorker(stopCh <-chan struct{}, cpu, memCPU int) {
+	defer wp.wg.Done()
+
+	if cpu >= 0 {
+		// The thread stays locked, so the runtime discards it when the
+		// worker exits rather than reusing it with the narrowed affinity
+		runtime.LockOSThread()
+		if memCPU < 0 {
+			pinThread(cpu)
+		} else {
+			pinThread(memCPU)
+		}
+	}
+	run := wp.workload.NewRun()
+	if cpu >= 0 && memCPU >= 0 {
+		pinThread(cpu)
+	}
+
 	v := rand.Float64()
 	var i uint64
+	cpuOps := wp.cpuOps[cpu]
+	flush := func() {
+		atomic.AddUint64(wp.counter, i)
+		if cpuOps != nil {
+			cpuOps.Add(i)
+		}
+		i = 0
+	}
 
 	for {
 		select {
 		case <-stopCh:
+			flush()
+			return
+		case <-wp.ctx.Done():
+			flush()
 			return
 		default:
-			// CPU-intensive floating-point operation
-			i++
-			v *= math.Pow(v, v)
+			// Idle while paused, with the unflushed operations counted
+			if gate := wp.pauseGate.Load(); gate != nil {
+				flush()
+				select {
+				case <-*gate:
+				case <-stopCh:
+					return
+				case <-wp.ctx.Done():
+					return
+				}
+				continue
+			}
+
+			// CPU-intensive batch of operations
+			v = run(batchSize, v)
+			i += batchSize
 
 			// Periodically update the shared counter
-			if i%1_000_000 == 0 {
-				atomic.AddUint64(wp.counter, i)
-				i = 0
+			if i >= flushSize {
+				flush()
 			}
 		}
 	}
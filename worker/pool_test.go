@@ -0,0 +1,362 @@
+package worker
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the timeout expires.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPoolScaling(t *testing.T) {
+	var counter uint64
+	wp := New(context.Background(), &counter, 2)
+	defer wp.Stop()
+
+	steps := []int{2, 5, 1, 0, 3}
+	for _, n := range steps {
+		wp.SetWorkers(n)
+		if got := wp.GetActiveCount(); got != n {
+			t.Fatalf("SetWorkers(%d): active count = %d", n, got)
+		}
+	}
+
+	wp.SetWorkers(-1)
+	if got := wp.GetActiveCount(); got != 0 {
+		t.Fatalf("SetWorkers(-1): active count = %d, want 0", got)
+	}
+}
+
+func TestPoolCountsOperations(t *testing.T) {
+	var counter uint64
+	wp := New(context.Background(), &counter, 1)
+
+	waitFor(t, func() bool { return atomic.LoadUint64(&counter) > 0 })
+	wp.Stop()
+
+	// Workers flush their partial batch on exit, then the counter is final
+	stopped := atomic.LoadUint64(&counter)
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadUint64(&counter); got != stopped {
+		t.Fatalf("counter changed after Stop: %d -> %d", stopped, got)
+	}
+}
+
+func TestPoolConcurrentSetWorkers(t *testing.T) {
+	var counter uint64
+	wp := New(context.Background(), &counter, 0)
+	defer wp.Stop()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				wp.SetWorkers((g + i) % 6)
+				_ = wp.GetActiveCount()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	wp.SetWorkers(4)
+	if got := wp.GetActiveCount(); got != 4 {
+		t.Fatalf("active count = %d, want 4", got)
+	}
+
+	wp.SetWorkers(1)
+	if got := wp.GetActiveCount(); got != 1 {
+		t.Fatalf("active count = %d, want 1", got)
+	}
+}
+
+func TestPoolStopWaitsForWorkers(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	var counter uint64
+	wp := New(context.Background(), &counter, 4)
+	wp.Stop()
+	wp.Stop() // Stop is idempotent
+
+	if got := wp.GetActiveCount(); got != 0 {
+		t.Fatalf("active count after Stop = %d, want 0", got)
+	}
+
+	// SetWorkers is a no-op once stopped
+	wp.SetWorkers(3)
+	if got := wp.GetActiveCount(); got != 0 {
+		t.Fatalf("active count after SetWorkers on stopped pool = %d, want 0", got)
+	}
+
+	waitFor(t, func() bool { return runtime.NumGoroutine() <= before })
+}
+
+func TestPoolContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var counter uint64
+	wp := New(ctx, &counter, 3)
+	cancel()
+
+	waitFor(t, func() bool { return wp.GetActiveCount() == 0 })
+	wp.Stop()
+}
+
+func TestPoolGOMAXPROCSOptIn(t *testing.T) {
+	orig := runtime.GOMAXPROCS(-1)
+	defer runtime.GOMAXPROCS(orig)
+
+	var counter uint64
+	wp := New(context.Background(), &counter, orig+1)
+	if got := runtime.GOMAXPROCS(-1); got != orig {
+		t.Fatalf("GOMAXPROCS changed without opt-in: %d -> %d", orig, got)
+	}
+	wp.Stop()
+
+	managed := New(context.Background(), &counter, orig+1, WithGOMAXPROCS())
+	if got := runtime.GOMAXPROCS(-1); got != orig+1 {
+		t.Fatalf("GOMAXPROCS = %d, want %d", got, orig+1)
+	}
+	managed.Stop()
+	if got := runtime.GOMAXPROCS(-1); got != orig {
+		t.Fatalf("GOMAXPROCS after Stop = %d, want %d", got, orig)
+	}
+}
+
+func TestPoolAddWorkersConcurrent(t *testing.T) {
+	var counter uint64
+	wp := New(context.Background(), &counter, 0)
+	defer wp.Stop()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				wp.AddWorkers(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := wp.GetActiveCount(); got != 200 {
+		t.Fatalf("active count = %d, want 200", got)
+	}
+	if got := wp.AddWorkers(-500); got != 0 {
+		t.Fatalf("AddWorkers(-500) = %d, want 0", got)
+	}
+}
+
+func TestPoolRemoveWorkersFloor(t *testing.T) {
+	var counter uint64
+	wp := New(context.Background(), &counter, 4)
+	defer wp.Stop()
+
+	// Concurrent removals never go below the floor
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wp.RemoveWorkers(1, 1)
+		}()
+	}
+	wg.Wait()
+	if got := wp.GetActiveCount(); got != 1 {
+		t.Fatalf("active count = %d, want 1", got)
+	}
+
+	// A pool below the floor is not grown
+	wp.SetWorkers(0)
+	if got := wp.RemoveWorkers(1, 1); got != 0 {
+		t.Fatalf("RemoveWorkers below the floor = %d, want 0", got)
+	}
+}
+
+func TestPoolEvents(t *testing.T) {
+	var counter uint64
+	wp := New(context.Background(), &counter, 1)
+
+	events, cancel := wp.Subscribe(16)
+	defer cancel()
+
+	wp.SetWorkers(3)
+	wp.SetWorkers(2)
+
+	want := []Event{
+		{Type: WorkerStarted, Worker: 1, Active: 2},
+		{Type: WorkerStarted, Worker: 2, Active: 3},
+		{Type: WorkerStopped, Worker: 2, Active: 2},
+	}
+	for _, w := range want {
+		got := <-events
+		if got.Type != w.Type || got.Worker != w.Worker || got.Active != w.Active {
+			t.Fatalf("event = %+v, want %+v", got, w)
+		}
+		if got.Time.IsZero() {
+			t.Fatalf("event %+v has no time", got)
+		}
+	}
+
+	// Stop reports the remaining workers, then closes the stream
+	wp.Stop()
+	var stops int
+	for ev := range events {
+		if ev.Type != WorkerStopped {
+			t.Fatalf("unexpected event after Stop: %+v", ev)
+		}
+		stops++
+	}
+	if stops != 2 {
+		t.Fatalf("got %d stop events, want 2", stops)
+	}
+}
+
+func TestPoolSubscribeCancel(t *testing.T) {
+	var counter uint64
+	wp := New(context.Background(), &counter, 0)
+	defer wp.Stop()
+
+	events, cancel := wp.Subscribe(1)
+	cancel()
+	if _, ok := <-events; ok {
+		t.Fatal("channel still open after cancel")
+	}
+
+	// A full buffer never blocks the control path
+	events, cancel = wp.Subscribe(1)
+	defer cancel()
+	wp.SetWorkers(5)
+	if got := len(events); got != 1 {
+		t.Fatalf("buffered events = %d, want 1", got)
+	}
+}
+
+func TestPoolPause(t *testing.T) {
+	var counter uint64
+	wp := New(context.Background(), &counter, 2)
+	defer wp.Stop()
+
+	events, cancel := wp.Subscribe(16)
+	defer cancel()
+
+	waitFor(t, func() bool { return atomic.LoadUint64(&counter) > 0 })
+	if !wp.Pause() || wp.Pause() {
+		t.Fatal("Pause should succeed once")
+	}
+	if ev := <-events; ev.Type != Paused || ev.Worker != -1 || ev.Active != 2 {
+		t.Fatalf("event = %+v, want paused", ev)
+	}
+
+	// Workers flush their batch and go idle, keeping their slots
+	time.Sleep(20 * time.Millisecond)
+	paused := atomic.LoadUint64(&counter)
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadUint64(&counter); got != paused {
+		t.Fatalf("counter changed while paused: %d -> %d", paused, got)
+	}
+	if got := wp.GetActiveCount(); got != 2 || !wp.Paused() {
+		t.Fatalf("paused pool: active = %d, paused = %v", got, wp.Paused())
+	}
+
+	if !wp.Resume() || wp.Resume() {
+		t.Fatal("Resume should succeed once")
+	}
+	if ev := <-events; ev.Type != Resumed {
+		t.Fatalf("event = %+v, want resumed", ev)
+	}
+	waitFor(t, func() bool { return atomic.LoadUint64(&counter) > paused })
+	if got := wp.PausedTime(); got < 40*time.Millisecond {
+		t.Fatalf("PausedTime = %s, want at least 40ms", got)
+	}
+}
+
+func TestPoolCPUs(t *testing.T) {
+	// Pin to the first CPU the test may run on
+	cpu := 0
+	if allowed := allowedCPUs(); allowed != nil {
+		for !allowed(cpu) {
+			cpu++
+		}
+	}
+
+	var counter uint64
+	wp := New(context.Background(), &counter, 3, WithCPUs([]int{cpu, -1}))
+	defer wp.Stop()
+
+	// Workers share the remaining CPU and still count
+	if cpus := wp.CPUs(); len(cpus) != 3 || cpus[0] != cpu || cpus[2] != cpu {
+		t.Fatalf("CPUs = %v, want three times %d", cpus, cpu)
+	}
+	waitFor(t, func() bool { return atomic.LoadUint64(&counter) > 0 })
+	wp.SetWorkers(1)
+	if cpus := wp.CPUs(); len(cpus) != 1 {
+		t.Errorf("CPUs after scaling down = %v", cpus)
+	}
+
+	unpinned := New(context.Background(), &counter, 1)
+	defer unpinned.Stop()
+	if cpus := unpinned.CPUs(); cpus != nil {
+		t.Errorf("CPUs of an unpinned pool = %v, want nil", cpus)
+	}
+}
+
+func TestPoolMemoryCPUs(t *testing.T) {
+	cpu := 0
+	if allowed := allowedCPUs(); allowed != nil {
+		for !allowed(cpu) {
+			cpu++
+		}
+	}
+	mem, ok := LookupWorkload("mem")
+	if !ok {
+		t.Fatal("mem workload missing")
+	}
+
+	var counter uint64
+	var calls atomic.Int32
+	wp := New(context.Background(), &counter, 2, WithWorkload(mem), WithCPUs([]int{cpu}),
+		WithMemoryCPUs(func(worker, c int) int {
+			calls.Add(1)
+			return c
+		}))
+	defer wp.Stop()
+
+	// Ops are counted per CPU as well as in total
+	waitFor(t, func() bool { return wp.CPUOps()[cpu] > 0 })
+	if got := calls.Load(); got != 2 {
+		t.Errorf("memory CPU asked %d times, want once per worker", got)
+	}
+
+	unpinned := New(context.Background(), &counter, 1, WithWorkload(mem))
+	defer unpinned.Stop()
+	if ops := unpinned.CPUOps(); ops != nil {
+		t.Errorf("CPUOps of an unpinned pool = %v, want nil", ops)
+	}
+}
+
+func TestWorkloadNewRun(t *testing.T) {
+	// A zero Workload runs the default one
+	for _, w := range append(Workloads(), Workload{}) {
+		run := w.NewRun()
+		if run == nil {
+			t.Fatalf("workload %q has no kernel", w.Name)
+		}
+		run(10, 1.5)
+	}
+}
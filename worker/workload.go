@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"math"
+	"runtime"
+	"sort"
+)
+
+// Workload is a CPU-bound kernel that workers run in batches. Workloads
+// come from LookupWorkload and Workloads; NewRun returns the kernel.
+type Workload struct {
+	Name        string
+	Description string
+
+	// CrossNode marks workloads meant to run with their memory on another
+	// NUMA node than their CPU, to load the interconnect between nodes.
+	CrossNode bool
+
+	run    func(n int, v float64) float64        // Stateless kernel, nil if newRun is set
+	newRun func() func(n int, v float64) float64 // Sets up a kernel with per-worker state
+}
+
+// NewRun returns the kernel of the workload. It performs n operations
+// starting from state v and returns the new state, which the caller feeds
+// back in so the work cannot be elided. Workloads with per-worker state,
+// such as a memory buffer, set it up in NewRun, so every worker calls it
+// on its own thread (see WithMemoryCPUs). A zero Workload runs the
+// default workload.
+func (w Workload) NewRun() func(n int, v float64) float64 {
+	switch {
+	case w.newRun != nil:
+		return w.newRun()
+	case w.run != nil:
+		return w.run
+	}
+	return workloads[DefaultWorkload].run
+}
+
+// memWords is the size of a memory workload's buffer, in 8-byte words:
+// 32 MiB per worker, well beyond the last-level cache share of a core.
+const memWords = 4 << 20
+
+// memStream is the state of a memory workload kernel.
+type memStream struct {
+	buf []uint64
+	j   int // Next word
+}
+
+// newMemRun allocates and first-touches a worker's memory buffer, and
+// returns a kernel that reads and writes one 64-byte cache line per
+// operation, streaming through the buffer. The buffer is released once
+// the kernel is unreachable.
+func newMemRun() func(n int, v float64) float64 {
+	buf, release := newBuffer(memWords)
+	for i := range buf {
+		buf[i] = uint64(i)
+	}
+	s := &memStream{buf: buf}
+	runtime.AddCleanup(s, func(release func()) { release() }, release)
+	return s.run
+}
+
+// run performs n operations of a memory workload.
+func (s *memStream) run(n int, v float64) float64 {
+	x := math.Float64bits(v)
+	for i := 0; i < n; i++ {
+		x += s.buf[s.j]
+		s.buf[s.j] = x
+		s.j += 8
+		if s.j >= len(s.buf) {
+			s.j = 0
+		}
+	}
+	return float64(x >> 11)
+}
+
+// DefaultWorkload is the workload used when none is selected.
+const DefaultWorkload = "pow"
+
+var workloads = map[string]Workload{
+	"pow": {
+		Name:        "pow",
+		Description: "floating-point math.Pow chain",
+		run: func(n int, v float64) float64 {
+			for i := 0; i < n; i++ {
+				v *= math.Pow(v, v)
+			}
+			return v
+		},
+	},
+	"int": {
+		Name:        "int",
+		Description: "integer xorshift and multiply chain",
+		run: func(n int, v float64) float64 {
+			x := math.Float64bits(v) | 1
+			for i := 0; i < n; i++ {
+				x ^= x << 13
+				x ^= x >> 7
+				x ^= x << 17
+				x *= 0x2545F4914F6CDD1D
+			}
+			return float64(x >> 11)
+		},
+	},
+	"mem": {
+		Name:        "mem",
+		Description: "memory read/write stream over a 32 MiB buffer per worker, on its own NUMA node",
+		newRun:      newMemRun,
+	},
+	"xnode": {
+		Name:        "xnode",
+		Description: "mem with every buffer on the next NUMA node, loading the interconnect",
+		newRun:      newMemRun,
+		CrossNode:   true,
+	},
+}
+
+// LookupWorkload returns the named built-in workload.
+func LookupWorkload(name string) (Workload, bool) {
+	w, ok := workloads[name]
+	return w, ok
+}
+
+// Workloads returns every built-in workload sorted by name.
+func Workloads() []Workload {
+	list := make([]Workload, 0, len(workloads))
+	for _, w := range workloads {
+		list = append(list, w)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// WithWorkload makes the pool's workers run w instead of the default.
+func WithWorkload(w Workload) Option {
+	return func(wp *Pool) {
+		wp.workload = w
+	}
+}